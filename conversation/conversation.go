@@ -0,0 +1,219 @@
+// Package conversation links messages together into dialogue trees.
+package conversation
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/L-P/mme/rom"
+)
+
+// noNextMessage is the MessageHeader.NextMessageNumber of messages that end a
+// conversation.
+const noNextMessage = 0xFFFF
+
+// A Node is a message in the graph.
+type Node struct {
+	ID      uint16
+	String  string
+	Choices []string `json:",omitempty"` // options offered to the player, if any
+}
+
+// An Edge links a message to the one displayed after it.
+// Which option leads where is decided by actor code, only choice targets
+// given by NextMessageNumber are part of the message data.
+type Edge struct {
+	From   uint16
+	To     uint16
+	Choice bool `json:",omitempty"` // To is displayed once an option is picked
+}
+
+// A Graph is a directed graph of messages.
+type Graph struct {
+	Nodes map[uint16]*Node
+	Edges []Edge
+
+	next map[uint16][]uint16
+	prev map[uint16][]uint16
+}
+
+// Build creates the graph of all messages.
+func Build(messages []rom.Message) *Graph {
+	g := &Graph{
+		Nodes: make(map[uint16]*Node, len(messages)),
+		Edges: make([]Edge, 0),
+		next:  make(map[uint16][]uint16),
+		prev:  make(map[uint16][]uint16),
+	}
+
+	for k := range messages {
+		msg := &messages[k]
+		g.Nodes[msg.ID] = &Node{
+			ID:      msg.ID,
+			String:  msg.String,
+			Choices: choices(msg.Tokens),
+		}
+	}
+
+	for _, msg := range messages {
+		if msg.NextMessageNumber == noNextMessage || msg.NextMessageNumber == msg.ID {
+			continue
+		}
+
+		if _, ok := g.Nodes[msg.NextMessageNumber]; !ok {
+			continue
+		}
+
+		g.Edges = append(g.Edges, Edge{
+			From:   msg.ID,
+			To:     msg.NextMessageNumber,
+			Choice: len(g.Nodes[msg.ID].Choices) > 0,
+		})
+		g.next[msg.ID] = append(g.next[msg.ID], msg.NextMessageNumber)
+		g.prev[msg.NextMessageNumber] = append(g.prev[msg.NextMessageNumber], msg.ID)
+	}
+
+	return g
+}
+
+// choices returns the options of a choice message, the game displays them on
+// the last lines of the box.
+func choices(tokens []rom.MessageToken) []string {
+	count := 0
+	lines := []string{""}
+	for _, token := range tokens {
+		switch token.Type {
+		case rom.MessageTokenText:
+			lines[len(lines)-1] += token.Text
+		case rom.MessageTokenLineBreak:
+			lines = append(lines, "")
+		case rom.MessageTokenBoxBreak:
+			lines = []string{""}
+		case rom.MessageTokenChoice:
+			count = 2
+			if token.Name == "choice3" {
+				count = 3
+			}
+		}
+	}
+
+	if count == 0 || count > len(lines) {
+		return nil
+	}
+
+	options := lines[len(lines)-count:]
+	for k := range options {
+		options[k] = strings.TrimSpace(options[k])
+	}
+
+	return options
+}
+
+// Thread returns the conversation the given message is part of: the messages
+// nothing leads to that eventually display it, and every message following
+// them, in the order they are displayed.
+func (g *Graph) Thread(id uint16) ([]*Node, []Edge) {
+	if _, ok := g.Nodes[id]; !ok {
+		return nil, nil
+	}
+
+	// Walk back to find the entry points leading to the message.
+	ancestors := map[uint16]bool{id: true}
+	queue := []uint16{id}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for _, other := range g.prev[cur] {
+			if !ancestors[other] {
+				ancestors[other] = true
+				queue = append(queue, other)
+			}
+		}
+	}
+
+	roots := make([]uint16, 0)
+	for other := range ancestors {
+		if len(g.prev[other]) == 0 {
+			roots = append(roots, other)
+		}
+	}
+	if len(roots) == 0 { // loop without an entry point
+		roots = append(roots, id)
+	}
+	sort.Slice(roots, func(i, j int) bool { return roots[i] < roots[j] })
+
+	// Then walk forward from them, every ancestor leads to the message so it
+	// is always reached.
+	nodes := make([]*Node, 0, len(ancestors))
+	visited := make(map[uint16]bool, len(ancestors))
+	queue = roots
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		if visited[cur] {
+			continue
+		}
+		visited[cur] = true
+		nodes = append(nodes, g.Nodes[cur])
+		queue = append(queue, g.next[cur]...)
+	}
+
+	edges := make([]Edge, 0)
+	for _, edge := range g.Edges {
+		if visited[edge.From] && visited[edge.To] {
+			edges = append(edges, edge)
+		}
+	}
+
+	return nodes, edges
+}
+
+// WriteDOT writes nodes and edges in the Graphviz DOT format.
+func WriteDOT(w io.Writer, nodes []*Node, edges []Edge) error {
+	if _, err := fmt.Fprintln(w, "digraph messages {\n\tnode [shape=box];"); err != nil {
+		return err
+	}
+
+	for _, node := range nodes {
+		label := fmt.Sprintf("0x%04X\n%s", node.ID, node.String)
+		if _, err := fmt.Fprintf(w, "\tm%04X [label=%s];\n", node.ID, dotQuote(label)); err != nil {
+			return err
+		}
+	}
+
+	for _, edge := range edges {
+		style := ""
+		if edge.Choice {
+			style = " [style=dashed]"
+		}
+		if _, err := fmt.Fprintf(w, "\tm%04X -> m%04X%s;\n", edge.From, edge.To, style); err != nil {
+			return err
+		}
+	}
+
+	_, err := fmt.Fprintln(w, "}")
+	return err
+}
+
+// WriteDOT writes the whole graph in the Graphviz DOT format, messages not
+// linked to any other are omitted.
+func (g *Graph) WriteDOT(w io.Writer) error {
+	nodes := make([]*Node, 0)
+	for id, node := range g.Nodes {
+		if len(g.next[id]) > 0 || len(g.prev[id]) > 0 {
+			nodes = append(nodes, node)
+		}
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].ID < nodes[j].ID })
+
+	return WriteDOT(w, nodes, g.Edges)
+}
+
+func dotQuote(s string) string {
+	s = strings.Replace(s, `\`, `\\`, -1)
+	s = strings.Replace(s, `"`, `\"`, -1)
+	s = strings.Replace(s, "\n", `\n`, -1)
+	return `"` + s + `"`
+}
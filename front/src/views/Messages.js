@@ -1,13 +1,39 @@
 export default {
   data() {
     return {
+      query: '',
+      offset: 0,
+      limit: 50,
+      total: 0,
       messages: [],
     };
   },
 
   mounted() {
-    this.$axios.get('/api/messages').then((res) => {
-      this.messages = res.data;
-    });
+    this.search();
+  },
+
+  methods: {
+    search(offset = 0) {
+      this.offset = offset;
+      const params = {
+        q: this.query,
+        offset: this.offset,
+        limit: this.limit,
+      };
+
+      this.$axios.get('/api/messages/search', { params }).then((res) => {
+        this.total = res.data.Total;
+        this.messages = res.data.Results;
+      });
+    },
+
+    previous() {
+      this.search(Math.max(0, this.offset - this.limit));
+    },
+
+    next() {
+      this.search(this.offset + this.limit);
+    },
   },
 };
@@ -1,38 +1,248 @@
 package main
 
 import (
+	"errors"
 	"flag"
+	"fmt"
+	"io"
 	"log"
 	"os"
+	"path/filepath"
+	"strconv"
+	"text/tabwriter"
 
+	"github.com/L-P/mme/conversation"
 	"github.com/L-P/mme/rom"
 	"github.com/L-P/mme/server"
+	"github.com/L-P/mme/textbox"
+	"github.com/L-P/mme/translation"
 )
 
 const colorMapPath = "out.png"
 
+// defaultActorParamsPath is loaded if it exists, other paths must exist.
+const defaultActorParamsPath = "actor_params.json"
+
 // Version holds the source tag mme was built from.
 var Version = "unknown version"
 
+var (
+	outPath       = flag.String("out", "", "write the ROM with the requested edits to this path instead of starting the server")
+	messageID     = flag.String("message", "", "ID of the message to edit, requires -markup")
+	messageMarkup = flag.String("markup", "", "new markup of the message to edit")
+	exportFormat  = flag.String("export-messages", "", "write all messages to stdout in this format (po, csv, xliff)")
+	importPath    = flag.String("import-messages", "", "import translated messages from this file (.po, .csv, .xliff)")
+	exportGraph   = flag.Bool("export-dialogue-graph", false, "write the graph of all conversations to stdout in the Graphviz DOT format")
+	actorParams   = flag.String("actor-params", defaultActorParamsPath, "load additional actor params schemas from this file, see data/actor_params.json")
+	collectibles  = flag.Bool("collectibles", false, "write every chest, heart piece, stray fairy, Skulltula and collectible item to stdout")
+	checkMessages = flag.Bool("check-messages", false, "report lines and boxes overflowing their text box, after applying edits")
+)
+
 func main() {
 	log.Printf("Majora's Mask Explorer %s", Version)
 	flag.Parse()
 
 	if len(flag.Args()) != 1 {
-		log.Printf("Usage: mme ROM")
+		log.Printf("Usage: mme [-export-messages FORMAT] [-export-dialogue-graph] [-collectibles] [-check-messages] [-out OUT] [-message ID -markup MARKUP] [-import-messages FILE] [-actor-params FILE] ROM")
 		os.Exit(1)
 	}
 
+	if err := checkEditFlags(); err != nil {
+		log.Fatal(err)
+	}
+
 	romPath := flag.Args()[0]
 
+	if err := loadActorParams(*actorParams); err != nil {
+		log.Fatal(err)
+	}
+
 	view, err := rom.NewView(romPath)
 	if err != nil {
 		log.Fatal(err)
 	}
 	defer view.Close()
 
-	server := server.New(view)
-	if err := server.ListenAndServe(); err != nil {
+	if *exportFormat != "" {
+		if err := exportMessages(view, *exportFormat); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	if *collectibles {
+		if err := writeCollectiblesReport(os.Stdout, view); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	if *exportGraph {
+		if err := conversation.Build(view.Messages).WriteDOT(os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	if *outPath == "" && !*checkMessages {
+		server := server.New(view)
+		if err := server.ListenAndServe(); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	if err := edit(view); err != nil {
 		log.Fatal(err)
 	}
+
+	if *outPath != "" {
+		if err := writeROM(view, *outPath); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	if *checkMessages {
+		overflows := textbox.CheckAll(view.Messages, view.FontWidths)
+		for _, overflow := range overflows {
+			fmt.Println(overflow)
+		}
+		if len(overflows) > 0 {
+			os.Exit(1)
+		}
+	}
+}
+
+// checkEditFlags ensures edits are written somewhere and that -message is not
+// used without -markup, an empty markup would wipe the message.
+func checkEditFlags() error {
+	markupSet := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == "markup" {
+			markupSet = true
+		}
+	})
+
+	if *messageID != "" && !markupSet {
+		return errors.New("-message requires -markup")
+	}
+
+	if (*messageID != "" || *importPath != "") && *outPath == "" {
+		return errors.New("-message and -import-messages require -out")
+	}
+
+	return nil
+}
+
+// edit applies the modifications requested on the command line.
+func edit(view *rom.View) error {
+	if *importPath != "" {
+		if err := importMessages(view, *importPath); err != nil {
+			return err
+		}
+	}
+
+	if *messageID == "" {
+		return nil
+	}
+
+	id, err := strconv.ParseUint(*messageID, 0, 16)
+	if err != nil {
+		return err
+	}
+
+	msg, err := view.GetMessageByID(uint16(id))
+	if err != nil {
+		return err
+	}
+
+	return view.SetMessage(uint16(id), msg.MessageHeader, *messageMarkup)
+}
+
+func exportMessages(view *rom.View, name string) error {
+	format, err := translation.ParseFormat(name)
+	if err != nil {
+		return err
+	}
+
+	return translation.Export(os.Stdout, view.Messages, format)
+}
+
+func importMessages(view *rom.View, path string) error {
+	format, err := translation.ParseFormat(filepath.Ext(path))
+	if err != nil {
+		return err
+	}
+
+	fd, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer fd.Close()
+
+	entries, err := translation.Import(fd, format)
+	if err != nil {
+		return err
+	}
+
+	if problems := translation.Validate(entries, view); len(problems) > 0 {
+		for _, problem := range problems {
+			log.Print(problem)
+		}
+		return fmt.Errorf("%d problems found in %s, nothing was imported", len(problems), path)
+	}
+
+	applied, err := translation.Apply(entries, view)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Imported %d messages", applied)
+
+	return nil
+}
+
+func writeROM(view *rom.View, path string) error {
+	fd, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer fd.Close()
+
+	if err := view.WriteROM(fd); err != nil {
+		return err
+	}
+
+	log.Printf("Modified ROM written to %s", path)
+
+	return fd.Close()
+}
+
+func loadActorParams(path string) error {
+	if _, err := os.Stat(path); os.IsNotExist(err) && path == defaultActorParamsPath {
+		return nil
+	}
+
+	return rom.LoadActorParamSchemasFile(path)
+}
+
+func writeCollectiblesReport(w io.Writer, view *rom.View) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "Scene\tRoom\tSetup\tKind\tActor\tContents\tFlag\tPosition")
+
+	for _, c := range view.Collectibles() {
+		flagDesc := "-"
+		if c.FlagType != "" {
+			flagDesc = fmt.Sprintf("%s 0x%02X", c.FlagType, c.Flag)
+		}
+
+		fmt.Fprintf(
+			tw,
+			"%s\t%d\t%d\t%s\t%s\t%s\t%s\t%d,%d,%d\n",
+			c.SceneName, c.RoomID, c.Setup, c.Kind, c.Name, c.Contents, flagDesc,
+			c.Position.X, c.Position.Y, c.Position.Z,
+		)
+	}
+
+	return tw.Flush()
 }
@@ -0,0 +1,76 @@
+package minimap
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+
+	"github.com/L-P/mme/rom"
+	"github.com/L-P/mme/texture"
+)
+
+// Dungeon map textures dimensions and format in map_i_static.
+const (
+	Width  = 96
+	Height = 85
+	Format = texture.I4
+)
+
+// TextureSize is the size of a single map texture in map_i_static.
+var TextureSize = Format.Size(Width, Height)
+
+const chestMarkerSize = 2 // half side of a chest marker, in pixels
+
+var (
+	backgroundColor  = color.NRGBA{0x20, 0x20, 0x20, 0xFF}
+	chestMarkerColor = color.NRGBA{0xFF, 0xC8, 0x00, 0xFF}
+)
+
+// Generate renders a Room dungeon map with its chest markers.
+// textures is the content of map_i_static, if nil the map is drawn over a
+// plain background.
+// Chest positions are projected using the minimap offset and scale, this is
+// an approximation of what the game does.
+func Generate(w io.Writer, room *rom.Room, textures []byte) error {
+	img := image.NewNRGBA(image.Rect(0, 0, Width, Height))
+	draw.Draw(img, img.Bounds(), &image.Uniform{backgroundColor}, image.Point{}, draw.Src)
+
+	if room.Minimap != nil && room.Minimap.HasMap() && textures != nil {
+		start := int(room.Minimap.MapID) * TextureSize
+		if start+TextureSize <= len(textures) {
+			tex, err := texture.Decode(textures[start:start+TextureSize], Format, Width, Height)
+			if err != nil {
+				return err
+			}
+			draw.Draw(img, img.Bounds(), tex, image.Point{}, draw.Over)
+		}
+	}
+
+	if room.Minimap != nil {
+		for _, chest := range room.MapChests {
+			x, y := project(room, chest.Position)
+			marker := image.Rect(
+				x-chestMarkerSize, y-chestMarkerSize,
+				x+chestMarkerSize+1, y+chestMarkerSize+1,
+			)
+			draw.Draw(img, marker, &image.Uniform{chestMarkerColor}, image.Point{}, draw.Src)
+		}
+	}
+
+	return png.Encode(w, img)
+}
+
+// project converts world X/Z coordinates to map pixels.
+func project(room *rom.Room, pos rom.Vec3) (int, int) {
+	scale := int(room.MinimapScale)
+	if scale == 0 {
+		scale = 1
+	}
+
+	x := (int(pos.X) - int(room.Minimap.OffsetX)) / scale
+	y := (int(pos.Z) - int(room.Minimap.OffsetZ)) / scale
+
+	return x, y
+}
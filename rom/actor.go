@@ -5,30 +5,42 @@ import (
 	"io"
 )
 
-// Vec3 is a simple x/y/z vector
+// Vec3 is a simple x/y/z vector, positions are in world units.
 type Vec3 struct {
-	X uint16
-	Y uint16
-	Z uint16
+	X int16
+	Y int16
+	Z int16
 }
 
 // ActorEntry are entries that point to the dynamic objects present in a Room.
 type ActorEntry struct {
 	ID                uint16
 	SpawnTimeFlags    uint16
+	SpawnHalfDays     []HalfDay // decoded SpawnTimeFlags
 	SceneCommandIndex byte
 	Initialization    uint16
 
-	NoXRotation bool
-	NoYRotation bool
-	NoZRotation bool
+	// Axes whose Rotation is a binary angle instead of degrees.
+	RawXRotation bool
+	RawYRotation bool
+	RawZRotation bool
 
 	Position Vec3
-	Rotation Vec3
+	Rotation Vec3 // raw 9-bit values, see RotationDegrees
+
+	// Rotation in degrees and binary angles (0x10000 being a full turn).
+	RotationDegrees Vec3
+	RotationAngles  Vec3
 
 	Description ActorDescription
+
+	Params            []ActorParam `json:",omitempty"` // decoded Initialization, see ActorParamSchemas
+	ParamsDescription string       `json:",omitempty"`
 }
 
+// fullTurn is a full turn in binary angle units.
+const fullTurn = 0x10000
+
 func (a *ActorEntry) load(r io.Reader) {
 	a.loadRotationFlagsAndID(r)                   // 2 bytes
 	binary.Read(r, binary.BigEndian, &a.Position) // 6 bytes
@@ -38,27 +50,70 @@ func (a *ActorEntry) load(r io.Reader) {
 	a.loadZRotationAndSpawnTimeFlags(r)                 // 2 bytes
 	binary.Read(r, binary.BigEndian, &a.Initialization) // 2 bytes
 
+	a.refresh()
+}
+
+// refresh sets the fields derived from the packed values.
+func (a *ActorEntry) refresh() {
+	a.RotationDegrees, a.RotationAngles = Vec3{}, Vec3{}
+	a.Params, a.ParamsDescription = nil, ""
+
+	a.loadRotationAngles()
+	a.loadSpawnHalfDays()
 	a.Description = ActorDescriptions[a.ID]
+	a.decodeParams()
+}
+
+func (a *ActorEntry) decodeParams() {
+	schema, ok := ActorParamSchemas[a.ID]
+	if !ok {
+		return
+	}
+
+	a.Params = schema.Decode(a.Initialization)
+	a.ParamsDescription = schema.Describe(a.Initialization)
+}
+
+// loadRotationAngles converts the raw rotation to degrees and binary angles,
+// it is stored in degrees unless the axis raw flag is set.
+func (a *ActorEntry) loadRotationAngles() {
+	for _, axis := range []struct {
+		binary           bool
+		raw, deg, binang *int16
+	}{
+		{a.RawXRotation, &a.Rotation.X, &a.RotationDegrees.X, &a.RotationAngles.X},
+		{a.RawYRotation, &a.Rotation.Y, &a.RotationDegrees.Y, &a.RotationAngles.Y},
+		{a.RawZRotation, &a.Rotation.Z, &a.RotationDegrees.Z, &a.RotationAngles.Z},
+	} {
+		if axis.binary {
+			*axis.binang = *axis.raw
+			*axis.deg = int16(int(*axis.raw) * 360 / fullTurn)
+			continue
+		}
+
+		*axis.deg = *axis.raw
+		*axis.binang = int16(uint16(int(*axis.raw) * fullTurn / 360))
+	}
 }
 
 func (a *ActorEntry) loadXRotationAndSpawnTimeFlags(r io.Reader) {
 	var v uint16
 	binary.Read(r, binary.BigEndian, &v)
-	a.Rotation.X = (v & 0xFF80) >> 7
+	a.Rotation.X = int16((v & 0xFF80) >> 7)
 	a.SpawnTimeFlags |= (v & 0x0007) << 7
 }
 
 func (a *ActorEntry) loadYRotationAndSceneCommandIndex(r io.Reader) {
 	var v uint16
 	binary.Read(r, binary.BigEndian, &v)
-	a.Rotation.Y = (v & 0xFF80) >> 7
+	a.Rotation.Y = int16((v & 0xFF80) >> 7)
 	a.SceneCommandIndex = byte(v & 0x007F)
 }
 
 func (a *ActorEntry) loadZRotationAndSpawnTimeFlags(r io.Reader) {
 	var v uint16
 	binary.Read(r, binary.BigEndian, &v)
-	a.Rotation.Z = (v & 0xFF80) >> 7
+	a.Rotation.Z = int16((v & 0xFF80) >> 7)
 	a.SpawnTimeFlags |= v & 0x007F
 }
 
@@ -67,9 +122,9 @@ func (a *ActorEntry) loadRotationFlagsAndID(r io.Reader) {
 	binary.Read(r, binary.BigEndian, &v)
 
 	// First three bits determine rotation options
-	a.NoXRotation = v&0x8000 > 0
-	a.NoYRotation = v&0x4000 > 0
-	a.NoZRotation = v&0x2000 > 0
+	a.RawYRotation = v&0x8000 > 0
+	a.RawXRotation = v&0x4000 > 0
+	a.RawZRotation = v&0x2000 > 0
 
 	// Rest of the value is the ID
 	a.ID = v & 0x0FFF
@@ -0,0 +1,355 @@
+package rom
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// actorEntrySize is the size of an actor entry in the room file.
+const actorEntrySize = 0x10
+
+// dmaDataStart is the ROM offset of ROM.DMAData.
+const dmaDataStart = 0x0001A500
+
+// actorsHeaderCommand is the header command holding the actor list.
+const actorsHeaderCommand = 0x01
+
+// Limits of the packed actor entry fields.
+const (
+	maxActorID           = 0x0FFF
+	maxActorRotation     = 0x01FF
+	maxSpawnTimeFlags    = 0x03FF
+	maxSceneCommandIndex = 0x7F
+)
+
+// encode returns the actor entry as stored in the room file, the reverse of
+// ActorEntry.load.
+func (a ActorEntry) encode() []byte {
+	id := a.ID & maxActorID
+	if a.RawYRotation {
+		id |= 0x8000
+	}
+	if a.RawXRotation {
+		id |= 0x4000
+	}
+	if a.RawZRotation {
+		id |= 0x2000
+	}
+
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.BigEndian, id)
+	binary.Write(&buf, binary.BigEndian, a.Position)
+	binary.Write(&buf, binary.BigEndian, uint16(a.Rotation.X)<<7|(a.SpawnTimeFlags>>7)&0x0007)
+	binary.Write(&buf, binary.BigEndian, uint16(a.Rotation.Y)<<7|uint16(a.SceneCommandIndex)&0x007F)
+	binary.Write(&buf, binary.BigEndian, uint16(a.Rotation.Z)<<7|a.SpawnTimeFlags&0x007F)
+	binary.Write(&buf, binary.BigEndian, a.Initialization)
+
+	return buf.Bytes()
+}
+
+// Validate returns an error if the actor cannot be encoded.
+func (a ActorEntry) Validate() error {
+	switch {
+	case a.ID > maxActorID:
+		return fmt.Errorf("actor ID 0x%04X is out of range", a.ID)
+	case a.SpawnTimeFlags > maxSpawnTimeFlags:
+		return fmt.Errorf("spawn time flags 0x%04X are out of range", a.SpawnTimeFlags)
+	case a.SceneCommandIndex > maxSceneCommandIndex:
+		return fmt.Errorf("scene command index 0x%02X is out of range", a.SceneCommandIndex)
+	}
+
+	for _, rot := range []int16{a.Rotation.X, a.Rotation.Y, a.Rotation.Z} {
+		if rot < 0 || rot > maxActorRotation {
+			return fmt.Errorf("rotation %d is out of range", rot)
+		}
+	}
+
+	return nil
+}
+
+// SetActor replaces the actor at index k, the room must then be saved with
+// View.SaveRoomActors.
+func (r *Room) SetActor(k int, actor ActorEntry) error {
+	if k < 0 || k >= len(r.ActorList) {
+		return fmt.Errorf("actor %d not found", k)
+	}
+	if err := actor.Validate(); err != nil {
+		return err
+	}
+
+	actor.refresh()
+	r.ActorList[k] = actor
+
+	return nil
+}
+
+// AddActor appends an actor and returns its index, the room must then be
+// saved with View.SaveRoomActors.
+func (r *Room) AddActor(actor ActorEntry) (int, error) {
+	if len(r.ActorList) >= 0xFF {
+		return 0, errors.New("actor list is full")
+	}
+	if err := actor.Validate(); err != nil {
+		return 0, err
+	}
+
+	actor.refresh()
+	r.ActorList = append(r.ActorList, actor)
+
+	return len(r.ActorList) - 1, nil
+}
+
+// DeleteActor removes the actor at index k, the room must then be saved with
+// View.SaveRoomActors.
+func (r *Room) DeleteActor(k int) error {
+	if k < 0 || k >= len(r.ActorList) {
+		return fmt.Errorf("actor %d not found", k)
+	}
+
+	r.ActorList = append(r.ActorList[:k], r.ActorList[k+1:]...)
+
+	return nil
+}
+
+// SetActors replaces the whole actor list, the room must then be saved with
+// View.SaveRoomActors.
+func (r *Room) SetActors(actors []ActorEntry) error {
+	if len(actors) > 0xFF {
+		return errors.New("too many actors")
+	}
+
+	list := make([]ActorEntry, len(actors), len(actors))
+	for k, actor := range actors {
+		if err := actor.Validate(); err != nil {
+			return fmt.Errorf("actor %d: %s", k, err)
+		}
+		actor.refresh()
+		list[k] = actor
+	}
+	r.ActorList = list
+
+	return nil
+}
+
+// actorsCommand returns the header command holding the main actor list.
+func (r *Room) actorsCommand() (*HeaderCommand, error) {
+	for k := range r.Commands {
+		if r.Commands[k].Command == actorsHeaderCommand {
+			return &r.Commands[k], nil
+		}
+	}
+
+	return nil, errors.New("room has no actor list header command")
+}
+
+// SaveRoomActors writes the room actor list back to the ROM. The list is
+// rewritten in place if it fits in its original space and no alternate setup
+// uses it, otherwise it is appended to the room file, which is moved to the
+// free space at the end of the ROM if the file following it leaves no room to
+// grow. The room VROMStart changes when it is moved. Nothing is written if
+// the list cannot be saved. The caller must hold the view Lock.
+func (v *View) SaveRoomActors(r *Room) error {
+	command, err := r.actorsCommand()
+	if err != nil {
+		return err
+	}
+
+	oldStart := r.VROMStart
+	file, fileIndex, err := v.fileByVROMStart(oldStart)
+	if err != nil {
+		return err
+	}
+
+	list := make([]byte, 0, len(r.ActorList)*actorEntrySize)
+	for _, actor := range r.ActorList {
+		list = append(list, actor.encode()...)
+	}
+
+	listOffset := r.ActorsSegmentOffset & 0x00FFFFFF
+	if len(r.ActorList) > r.actorCapacity || r.actorListShared() {
+		listOffset = (uint32(file.Size()) + 0x0F) &^ 0x0F
+		grown := make([]byte, listOffset, int(listOffset)+len(list))
+		copy(grown, file.data)
+		grown = append(grown, list...)
+
+		// Check everything before patching so a failure leaves the ROM as is.
+		if _, err := v.resizeDestination(fileIndex, uint32(len(grown)), true); err != nil {
+			return err
+		}
+		entries, err := v.roomListEntries(r, oldStart)
+		if err != nil {
+			return err
+		}
+
+		if err := v.resizeFile(fileIndex, grown, true); err != nil {
+			return err
+		}
+		v.moveRoom(r, file, oldStart, entries)
+
+		r.ActorsSegmentOffset = roomSegment<<24 | listOffset
+		r.actorCapacity = len(r.ActorList)
+	}
+
+	v.patch(r.VROMStart+listOffset, list)
+
+	r.ActorsCount = byte(len(r.ActorList))
+	command.A = command.A&0xFF00FFFF | uint32(r.ActorsCount)<<16
+	command.B = r.ActorsSegmentOffset
+	command.Values = HeaderValues{"ActorsCount": r.ActorsCount, "ActorsSegmentOffset": command.B}
+
+	header := make([]byte, 8, 8)
+	binary.BigEndian.PutUint32(header[0:], command.A)
+	binary.BigEndian.PutUint32(header[4:], command.B)
+	v.patch(command.Offset, header)
+
+	return nil
+}
+
+// actorListShared returns true if an alternate setup with its own header
+// points to the main actor list.
+func (r *Room) actorListShared() bool {
+	for _, setup := range r.AlternateSetups {
+		if setup.HeaderSegmentOffset != 0 && setup.ActorsCount > 0 &&
+			setup.ActorsSegmentOffset&0x00FFFFFF == r.ActorsSegmentOffset&0x00FFFFFF {
+			return true
+		}
+	}
+
+	return false
+}
+
+// fileByVROMStart is GetFileByVROMStart also returning the file index.
+func (v *View) fileByVROMStart(start uint32) (*File, int, error) {
+	for k := range v.Files {
+		if v.Files[k].Valid && v.Files[k].VROMStart == start {
+			return &v.Files[k], k, nil
+		}
+	}
+
+	return nil, 0, fmt.Errorf("file 0x%08X not found", start)
+}
+
+// resizeFile replaces the data of a file with data of a different size. The
+// file grows in place if no other file follows it closely, otherwise it is
+// moved to the free space at the end of the ROM if movable is true. The DMA
+// table is updated.
+func (v *View) resizeFile(k int, data []byte, movable bool) error {
+	start, err := v.resizeDestination(k, uint32(len(data)), movable)
+	if err != nil {
+		return err
+	}
+
+	file := &v.Files[k]
+	size := uint32(len(data))
+
+	// We work on decompressed ROMs, physical offsets are the virtual ones.
+	file.VROMStart, file.VROMEnd = start, start+size
+	file.PROMStart = start
+	if file.PROMEnd != 0 {
+		file.PROMEnd = file.VROMEnd
+	}
+	file.data = data
+	v.rom.DMAData[k] = file.DMAEntry
+
+	entry := make([]byte, 16, 16)
+	binary.BigEndian.PutUint32(entry[0:], file.VROMStart)
+	binary.BigEndian.PutUint32(entry[4:], file.VROMEnd)
+	binary.BigEndian.PutUint32(entry[8:], file.PROMStart)
+	binary.BigEndian.PutUint32(entry[12:], file.PROMEnd)
+	v.patch(dmaDataStart+uint32(k)*16, entry)
+	v.patch(file.VROMStart, data)
+
+	return nil
+}
+
+// resizeDestination returns where resizeFile would put file k once resized
+// to size bytes, without changing anything.
+func (v *View) resizeDestination(k int, size uint32, movable bool) (uint32, error) {
+	file := &v.Files[k]
+
+	start := file.VROMStart
+	for _, other := range v.Files {
+		if other.Valid && other.VROMStart != file.VROMStart &&
+			other.VROMStart < start+size && other.VROMEnd > start {
+			if !movable {
+				return 0, fmt.Errorf("file 0x%08X cannot grow past file 0x%08X", file.VROMStart, other.VROMStart)
+			}
+			start = v.freeSpaceStart()
+			break
+		}
+	}
+
+	if start+size > Size {
+		return 0, fmt.Errorf("not enough free space in the ROM, %d bytes needed", size)
+	}
+
+	return start, nil
+}
+
+// freeSpaceStart returns the first 16-byte aligned offset after every file.
+func (v *View) freeSpaceStart() uint32 {
+	var end uint32
+	for _, file := range v.Files {
+		if file.Valid && file.VROMEnd > end {
+			end = file.VROMEnd
+		}
+	}
+
+	return (end + 0x0F) &^ 0x0F
+}
+
+// roomListEntries returns the VROM offsets of the scene room list entries
+// pointing to the room file starting at oldStart.
+func (v *View) roomListEntries(r *Room, oldStart uint32) ([]uint32, error) {
+	scene, err := v.GetSceneByVROMStart(r.SceneVROMStart)
+	if err != nil {
+		return nil, err
+	}
+
+	sceneFile, _, err := v.fileByVROMStart(scene.VROMStart)
+	if err != nil {
+		return nil, err
+	}
+
+	// Room list entries are VROM start and end pairs.
+	listOffset := scene.RoomsSegmentOffset & 0x00FFFFFF
+	entries := make([]uint32, 0, 1)
+	for i := uint32(0); i < uint32(scene.RoomsCount); i++ {
+		pos := listOffset + i*8
+		if pos+8 > uint32(sceneFile.Size()) || binary.BigEndian.Uint32(sceneFile.data[pos:]) != oldStart {
+			continue
+		}
+		entries = append(entries, sceneFile.VROMStart+pos)
+	}
+
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("room 0x%08X not found in its scene room list", oldStart)
+	}
+
+	return entries, nil
+}
+
+// moveRoom updates a room and its scene room list entries after its file was
+// resized and possibly moved from oldStart.
+func (v *View) moveRoom(r *Room, file *File, oldStart uint32, entries []uint32) {
+	for _, offset := range entries {
+		entry := make([]byte, 8, 8)
+		binary.BigEndian.PutUint32(entry[0:], file.VROMStart)
+		binary.BigEndian.PutUint32(entry[4:], file.VROMEnd)
+		v.patch(offset, entry)
+	}
+
+	delta := file.VROMStart - oldStart // offsets wrap around, moving backwards works too
+	r.VROMStart = file.VROMStart
+	r.DataStartOffset += delta
+	for k := range r.Commands {
+		r.Commands[k].Offset += delta
+	}
+	for k := range r.AlternateSetups {
+		for i := range r.AlternateSetups[k].Commands {
+			r.AlternateSetups[k].Commands[i].Offset += delta
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package rom
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestActorEntryRoundTrip(t *testing.T) {
+	cases := []struct {
+		name     string
+		data     []byte
+		expected ActorEntry
+	}{
+		{
+			name: "plain",
+			data: []byte{0x00, 0x06, 0x00, 0x10, 0x00, 0x20, 0x00, 0x30, 0x00, 0x00, 0x2D, 0x00, 0x00, 0x00, 0x0A, 0x27},
+			expected: ActorEntry{
+				ID:             0x0006,
+				Position:       Vec3{0x10, 0x20, 0x30},
+				Rotation:       Vec3{0, 90, 0},
+				Initialization: 0x0A27,
+			},
+		},
+		{
+			name: "negative position",
+			data: []byte{0x00, 0x0E, 0xFF, 0xF6, 0x00, 0x00, 0x80, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xFF, 0xFF},
+			expected: ActorEntry{
+				ID:             0x000E,
+				Position:       Vec3{-10, 0, -32768},
+				Initialization: 0xFFFF,
+			},
+		},
+		{
+			name: "spawn time and scene command",
+			data: []byte{0x01, 0xB0, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x07, 0x00, 0x12, 0x00, 0x7F, 0x00, 0x00},
+			expected: ActorEntry{
+				ID:                0x01B0,
+				SpawnTimeFlags:    0x3FF,
+				SceneCommandIndex: 0x12,
+			},
+		},
+		{
+			name: "raw rotations",
+			data: []byte{0xE0, 0x2A, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xFF, 0x80, 0x00, 0x80, 0x80, 0x00, 0x00, 0x00},
+			expected: ActorEntry{
+				ID:           0x002A,
+				RawXRotation: true,
+				RawYRotation: true,
+				RawZRotation: true,
+				Rotation:     Vec3{0x1FF, 1, 0x100},
+			},
+		},
+		{
+			name: "Y rotation flag only",
+			data: []byte{0x80, 0x2A, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+			expected: ActorEntry{
+				ID:           0x002A,
+				RawYRotation: true,
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var actor ActorEntry
+			actor.load(bytes.NewReader(c.data))
+
+			// Only compare the packed fields.
+			got := actor
+			got.SpawnHalfDays, got.RotationDegrees, got.RotationAngles = nil, Vec3{}, Vec3{}
+			got.Description, got.Params, got.ParamsDescription = ActorDescription{}, nil, ""
+			if !reflect.DeepEqual(got, c.expected) {
+				t.Fatalf("expected %+v, got %+v", c.expected, got)
+			}
+
+			if encoded := actor.encode(); !bytes.Equal(encoded, c.data) {
+				t.Fatalf("expected % X, got % X", c.data, encoded)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package rom
+
+import "sort"
+
+// ActorOccurrence locates an actor placed in a room.
+type ActorOccurrence struct {
+	SceneVROMStart uint32
+	SceneName      string
+	RoomVROMStart  uint32
+	RoomID         byte
+	Setup          int // 0 for the main header, see RoomSetup
+	Index          int // in the setup actor list
+
+	Position          Vec3
+	RotationDegrees   Vec3
+	Initialization    uint16
+	ParamsDescription string `json:",omitempty"`
+}
+
+// ActorSummary counts the occurrences of an actor.
+type ActorSummary struct {
+	ActorDescription
+	Count int
+	Rooms int // distinct rooms
+}
+
+// ActorOccurrences returns every actor placement in the game, including
+// alternate setups, by actor ID.
+func (v *View) ActorOccurrences() map[uint16][]ActorOccurrence {
+	index := make(map[uint16][]ActorOccurrence, len(ActorDescriptions))
+
+	v.eachActor(func(occurrence ActorOccurrence, actor ActorEntry) {
+		index[actor.ID] = append(index[actor.ID], occurrence)
+	})
+
+	return index
+}
+
+// eachActor calls fn for every actor placed in the game, including in
+// alternate setups.
+func (v *View) eachActor(fn func(ActorOccurrence, ActorEntry)) {
+	for k := range v.Scenes {
+		v.Scenes[k].eachActor(fn)
+	}
+}
+
+// eachActor calls fn for every actor placed in the scene, including in
+// alternate setups.
+func (s *Scene) eachActor(fn func(ActorOccurrence, ActorEntry)) {
+	for _, room := range s.Rooms {
+		add := func(setup int, actors []ActorEntry) {
+			for k, actor := range actors {
+				fn(ActorOccurrence{
+					SceneVROMStart:    s.VROMStart,
+					SceneName:         s.Name,
+					RoomVROMStart:     room.VROMStart,
+					RoomID:            room.ID,
+					Setup:             setup,
+					Index:             k,
+					Position:          actor.Position,
+					RotationDegrees:   actor.RotationDegrees,
+					Initialization:    actor.Initialization,
+					ParamsDescription: actor.ParamsDescription,
+				}, actor)
+			}
+		}
+
+		add(0, room.ActorList)
+		for _, setup := range room.AlternateSetups {
+			add(setup.Index, setup.ActorList)
+		}
+	}
+}
+
+// ActorSummaries returns the occurrence counts of every placed actor, sorted
+// by actor ID.
+func (v *View) ActorSummaries() []ActorSummary {
+	index := v.ActorOccurrences()
+	summaries := make([]ActorSummary, 0, len(index))
+
+	for id, occurrences := range index {
+		rooms := make(map[uint32]struct{}, len(occurrences))
+		for _, occurrence := range occurrences {
+			rooms[occurrence.RoomVROMStart] = struct{}{}
+		}
+
+		description, ok := ActorDescriptions[id]
+		if !ok {
+			description.ID = id
+		}
+
+		summaries = append(summaries, ActorSummary{
+			ActorDescription: description,
+			Count:            len(occurrences),
+			Rooms:            len(rooms),
+		})
+	}
+
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].ID < summaries[j].ID
+	})
+
+	return summaries
+}
@@ -0,0 +1,205 @@
+package rom
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+// ActorParamSchema describes the bitfields packed in the Initialization
+// params of an actor.
+type ActorParamSchema struct {
+	ID     uint16
+	Name   string
+	Fields []ActorParamField
+}
+
+// ActorParamField is a bitfield of the actor params, values are shifted by
+// the number of trailing zeros of the mask.
+type ActorParamField struct {
+	Name   string
+	Mask   uint16
+	Values map[uint16]string // optional names for the values
+
+	// Flag is set if the field holds a scene flag, None is the value meaning
+	// no flag is used.
+	Flag   FlagType
+	Access FlagAccess
+	None   *uint16
+
+	// Clear lists the values making the actor test the clear flag of its
+	// room.
+	Clear []uint16
+}
+
+// ActorParam is a decoded ActorParamField.
+type ActorParam struct {
+	Name      string
+	Value     uint16
+	ValueName string `json:",omitempty"`
+
+	Flag   FlagType   `json:",omitempty"` // empty if the field is not a flag or no flag is used
+	Access FlagAccess `json:",omitempty"`
+}
+
+// FlagValue returns the flag the param refers to, clear flags are the room
+// of the actor and not the param value.
+func (p ActorParam) FlagValue(room byte) uint16 {
+	if p.Flag == FlagClear {
+		return uint16(room)
+	}
+
+	return p.Value
+}
+
+// ActorParamSchemas maps actor IDs to their params schema, it is loaded from
+// data/actor_params.json and can be extended with LoadActorParamSchemas.
+var ActorParamSchemas = map[uint16]ActorParamSchema{}
+
+// rawActorParamSchema is the JSON form of ActorParamSchema, masks and value
+// keys are strings to allow hexadecimal.
+type rawActorParamSchema struct {
+	ID     uint16
+	Name   string
+	Fields []struct {
+		Name   string
+		Mask   string
+		Values map[string]string
+		Flag   FlagType
+		Access FlagAccess
+		None   string
+		Clear  []string
+	}
+}
+
+// Decode extracts a field from params.
+func (f ActorParamField) Decode(params uint16) ActorParam {
+	value := (params & f.Mask) >> uint(bits.TrailingZeros16(f.Mask))
+	param := ActorParam{Name: f.Name, Value: value, ValueName: f.Values[value]}
+	if f.Flag != "" && (f.None == nil || *f.None != value) {
+		param.Flag, param.Access = f.Flag, f.Access
+	}
+	for _, v := range f.Clear {
+		if v == value {
+			param.Flag, param.Access = FlagClear, FlagTest
+		}
+	}
+
+	return param
+}
+
+// String returns the field as "name=value".
+func (p ActorParam) String() string {
+	if p.ValueName != "" {
+		return fmt.Sprintf("%s=%s", p.Name, p.ValueName)
+	}
+
+	return fmt.Sprintf("%s=0x%02X", p.Name, p.Value)
+}
+
+// Decode extracts all fields from params.
+func (s ActorParamSchema) Decode(params uint16) []ActorParam {
+	decoded := make([]ActorParam, len(s.Fields), len(s.Fields))
+	for k, field := range s.Fields {
+		decoded[k] = field.Decode(params)
+	}
+
+	return decoded
+}
+
+// Describe returns decoded params as "Name: field=value, field=value".
+func (s ActorParamSchema) Describe(params uint16) string {
+	decoded := s.Decode(params)
+	fields := make([]string, len(decoded), len(decoded))
+	for k, param := range decoded {
+		fields[k] = param.String()
+	}
+
+	return fmt.Sprintf("%s: %s", s.Name, strings.Join(fields, ", "))
+}
+
+// LoadActorParamSchemas adds schemas from JSON data in the same format as
+// data/actor_params.json, replacing existing schemas of the same actors.
+// Schemas must be loaded before the ROM to be applied.
+func LoadActorParamSchemas(data []byte) error {
+	list := make([]rawActorParamSchema, 0, 64)
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+
+	for _, raw := range list {
+		schema, err := raw.compile()
+		if err != nil {
+			return fmt.Errorf("actor 0x%04X: %s", raw.ID, err)
+		}
+		ActorParamSchemas[schema.ID] = schema
+	}
+
+	return nil
+}
+
+// LoadActorParamSchemasFile is LoadActorParamSchemas reading from a file.
+func LoadActorParamSchemasFile(path string) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	if err := LoadActorParamSchemas(data); err != nil {
+		return fmt.Errorf("%s: %s", path, err)
+	}
+
+	return nil
+}
+
+func (raw rawActorParamSchema) compile() (ActorParamSchema, error) {
+	schema := ActorParamSchema{
+		ID:     raw.ID,
+		Name:   raw.Name,
+		Fields: make([]ActorParamField, len(raw.Fields), len(raw.Fields)),
+	}
+
+	for k, rawField := range raw.Fields {
+		mask, err := strconv.ParseUint(rawField.Mask, 0, 16)
+		if err != nil || mask == 0 {
+			return schema, fmt.Errorf("field %s: invalid mask %q", rawField.Name, rawField.Mask)
+		}
+
+		field := ActorParamField{
+			Name:   rawField.Name,
+			Mask:   uint16(mask),
+			Values: make(map[uint16]string, len(rawField.Values)),
+			Flag:   rawField.Flag,
+			Access: rawField.Access,
+		}
+		if rawField.None != "" {
+			none, err := strconv.ParseUint(rawField.None, 0, 16)
+			if err != nil {
+				return schema, fmt.Errorf("field %s: invalid none value %q", rawField.Name, rawField.None)
+			}
+			field.None = new(uint16)
+			*field.None = uint16(none)
+		}
+		for key, name := range rawField.Values {
+			value, err := strconv.ParseUint(key, 0, 16)
+			if err != nil {
+				return schema, fmt.Errorf("field %s: invalid value %q", rawField.Name, key)
+			}
+			field.Values[uint16(value)] = name
+		}
+		for _, key := range rawField.Clear {
+			value, err := strconv.ParseUint(key, 0, 16)
+			if err != nil {
+				return schema, fmt.Errorf("field %s: invalid clear value %q", rawField.Name, key)
+			}
+			field.Clear = append(field.Clear, uint16(value))
+		}
+
+		schema.Fields[k] = field
+	}
+
+	return schema, nil
+}
@@ -0,0 +1,64 @@
+package rom
+
+import "testing"
+
+func TestActorParamFieldDecode(t *testing.T) {
+	none := uint16(0x7F)
+	cases := []struct {
+		name     string
+		field    ActorParamField
+		params   uint16
+		expected ActorParam
+	}{
+		{
+			name:     "low bits",
+			field:    ActorParamField{Name: "type", Mask: 0x00FF},
+			params:   0x1234,
+			expected: ActorParam{Name: "type", Value: 0x34},
+		},
+		{
+			name:     "shifted",
+			field:    ActorParamField{Name: "flag", Mask: 0x7F00},
+			params:   0x9234,
+			expected: ActorParam{Name: "flag", Value: 0x12},
+		},
+		{
+			name:     "sparse mask",
+			field:    ActorParamField{Name: "item", Mask: 0x0FE0},
+			params:   0xF1E5,
+			expected: ActorParam{Name: "item", Value: 0x0F},
+		},
+		{
+			name:     "value name",
+			field:    ActorParamField{Name: "area", Mask: 0x01E0, Values: map[uint16]string{2: "Snowhead"}},
+			params:   0x0040,
+			expected: ActorParam{Name: "area", Value: 2, ValueName: "Snowhead"},
+		},
+		{
+			name:     "flag",
+			field:    ActorParamField{Name: "flag", Mask: 0x007F, Flag: FlagSwitch, Access: FlagSet, None: &none},
+			params:   0x0005,
+			expected: ActorParam{Name: "flag", Value: 5, Flag: FlagSwitch, Access: FlagSet},
+		},
+		{
+			name:     "no flag",
+			field:    ActorParamField{Name: "flag", Mask: 0x007F, Flag: FlagSwitch, Access: FlagSet, None: &none},
+			params:   0xFF7F,
+			expected: ActorParam{Name: "flag", Value: 0x7F},
+		},
+		{
+			name:     "flag without none",
+			field:    ActorParamField{Name: "flag", Mask: 0x001F, Flag: FlagChest, Access: FlagSet},
+			params:   0x001F,
+			expected: ActorParam{Name: "flag", Value: 0x1F, Flag: FlagChest, Access: FlagSet},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := c.field.Decode(c.params); got != c.expected {
+				t.Fatalf("expected %+v, got %+v", c.expected, got)
+			}
+		})
+	}
+}
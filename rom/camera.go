@@ -0,0 +1,123 @@
+package rom
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// CameraEntry is a single entry of either a bg camera list (collision) or an
+// actor camera list (0x02 header command).
+// binpacked, do not change struct size
+type CameraEntry struct {
+	Setting           uint16
+	DataCount         int16
+	DataSegmentOffset uint32
+}
+
+// Camera is a fixed or constrained camera the game switches to.
+type Camera struct {
+	CameraEntry
+
+	SettingName string
+	Data        []Vec3 // raw camera function data
+
+	// Only set for cameras having position, rotation and FOV data.
+	HasPosition bool
+	Position    Vec3
+	Rotation    Vec3
+	FOV         int16
+
+	// Indices of the collision polygons using this camera.
+	Polygons []uint16
+}
+
+func loadCameras(r io.ReadSeeker, base uint32, segmentOffset uint32, count int) []Camera {
+	cameras := make([]Camera, count, count)
+	if count <= 0 {
+		return cameras
+	}
+
+	seekSegment(r, base, segmentOffset)
+	for k := range cameras {
+		binary.Read(r, binary.BigEndian, &cameras[k].CameraEntry)
+	}
+
+	// Load data after because we need to keep our reader seek position.
+	for k := range cameras {
+		cameras[k].loadData(r, base)
+	}
+
+	return cameras
+}
+
+func (c *Camera) loadData(r io.ReadSeeker, base uint32) {
+	c.SettingName = CameraSettingName(c.Setting)
+	if c.DataSegmentOffset == 0 || c.DataCount <= 0 {
+		return
+	}
+
+	c.Data = make([]Vec3, c.DataCount, c.DataCount)
+	seekSegment(r, base, c.DataSegmentOffset)
+	binary.Read(r, binary.BigEndian, c.Data)
+
+	// Position, rotation, then FOV and flags.
+	if len(c.Data) >= 3 {
+		c.HasPosition = true
+		c.Position = c.Data[0]
+		c.Rotation = c.Data[1]
+		c.FOV = c.Data[2].X
+	}
+}
+
+// CameraSettingName returns a human-readable name for a camera setting type.
+func CameraSettingName(setting uint16) string {
+	if name, ok := CameraSettingNames[setting]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("unknown 0x%02X", setting)
+}
+
+// CameraSettingNames maps camera setting types to their name.
+// Sources:
+// - https://github.com/zeldaret/mm/blob/master/include/z64camera.h
+var CameraSettingNames = map[uint16]string{
+	0x00: "NONE",
+	0x01: "NORMAL0",
+	0x02: "NORMAL3",
+	0x03: "PIVOT_DIVING",
+	0x04: "HORSE",
+	0x05: "NORMAL4",
+	0x06: "ZORA_DIVING",
+	0x07: "PREREND_FIXED",
+	0x08: "PREREND_PIVOT",
+	0x09: "DOORC",
+	0x0A: "DEMO0",
+	0x0B: "FREE0",
+	0x0C: "FUKAN0",
+	0x0D: "NORMAL1",
+	0x0E: "NANAME",
+	0x0F: "CIRCLE0",
+	0x10: "FIXED0",
+	0x11: "SPIRAL_DOOR",
+	0x12: "DUNGEON0",
+	0x13: "ITEM0",
+	0x14: "ITEM1",
+	0x15: "ITEM2",
+	0x16: "ITEM3",
+	0x17: "NAVI",
+	0x18: "WARP_PAD_MOON",
+	0x19: "DEATH",
+	0x1A: "REBIRTH",
+	0x1B: "LONG_CHEST_OPENING",
+	0x1C: "MASK_TRANSFORMATION",
+	0x1D: "ATTENTION",
+	0x1E: "WARP_PAD_ENTRANCE",
+	0x1F: "DUNGEON1",
+	0x20: "FIXED1",
+	0x21: "FIXED2",
+	0x22: "MAZE",
+	0x23: "REMOTEBOMB",
+	0x24: "CIRCLE1",
+}
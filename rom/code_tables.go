@@ -0,0 +1,111 @@
+package rom
+
+import (
+	"encoding/binary"
+	"log"
+)
+
+// ActorOverlay is an entry of the actor overlay table located in code, it
+// maps actor IDs to the overlay file holding their code.
+// Sources:
+// - https://wiki.cloudmodding.com/mm/Actor_Overlay_Table
+// binpacked, do not change struct size
+type ActorOverlay struct {
+	VROMStart     uint32 // 0 for actors in code
+	VROMEnd       uint32
+	VRAMStart     uint32
+	VRAMEnd       uint32
+	LoadedRAMAddr uint32
+	InitInfo      uint32 // VRAM address of the ActorInit
+	Name          uint32
+	AllocType     uint16
+	NumLoaded     int8
+	_             byte
+}
+
+// Actor overlay allocation types, only normal overlays use the actor heap.
+const (
+	ActorAllocNormal    = 0
+	ActorAllocAbsolute  = 1
+	ActorAllocPermanent = 2
+)
+
+// ObjectEntry is an entry of the object table located in code, it maps object
+// IDs to their file.
+// binpacked, do not change struct size
+type ObjectEntry struct {
+	VROMStart uint32
+	VROMEnd   uint32
+}
+
+// Size returns the number of bytes the overlay uses in RAM, bss included.
+func (o ActorOverlay) Size() uint32 {
+	if o.VROMStart == 0 {
+		return 0
+	}
+
+	return o.VRAMEnd - o.VRAMStart
+}
+
+// Size returns the object file size.
+func (o ObjectEntry) Size() uint32 {
+	return o.VROMEnd - o.VROMStart
+}
+
+// actorInitInstanceSize is the offset of instanceSize in ActorInit.
+const actorInitInstanceSize = 0x0C
+
+// loadCodeTables copies the actor overlay and object tables, entries must be
+// empty or point to a file of the DMA table.
+func (v *View) loadCodeTables() {
+	files := make(map[uint32]uint32, len(v.Files))
+	for _, file := range v.Files {
+		if file.Valid {
+			files[file.VROMStart] = file.VROMEnd
+		}
+	}
+
+	isFile := func(start, end uint32) bool {
+		fileEnd, ok := files[start]
+		return (start == 0 && end == 0) || (ok && fileEnd == end)
+	}
+
+	for _, overlay := range v.rom.ActorOverlayTable {
+		if !isFile(overlay.VROMStart, overlay.VROMEnd) {
+			log.Print("Actor overlay table is invalid")
+			return
+		}
+	}
+	v.ActorOverlays = append([]ActorOverlay(nil), v.rom.ActorOverlayTable[:]...)
+	log.Printf("Loaded %d actor overlays", len(v.ActorOverlays))
+
+	for _, object := range v.rom.ObjectTable {
+		if !isFile(object.VROMStart, object.VROMEnd) {
+			log.Print("Object table is invalid")
+			return
+		}
+	}
+	v.Objects = append([]ObjectEntry(nil), v.rom.ObjectTable[:]...)
+	log.Printf("Loaded %d objects", len(v.Objects))
+}
+
+// InstanceSize returns the size of an actor instance, read from the
+// ActorInit of its overlay, 0 if unknown (actors in code).
+func (v *View) InstanceSize(id uint16) uint32 {
+	if int(id) >= len(v.ActorOverlays) {
+		return 0
+	}
+
+	overlay := v.ActorOverlays[id]
+	file, err := v.GetFileByVROMStart(overlay.VROMStart)
+	if overlay.VROMStart == 0 || err != nil {
+		return 0
+	}
+
+	offset := overlay.InitInfo - overlay.VRAMStart + actorInitInstanceSize
+	if overlay.InitInfo < overlay.VRAMStart || int(offset)+4 > file.Size() {
+		return 0
+	}
+
+	return binary.BigEndian.Uint32(file.data[offset:])
+}
@@ -0,0 +1,160 @@
+package rom
+
+// CollectibleKind is the kind of a Collectible.
+type CollectibleKind string
+
+// Collectible kinds
+const (
+	CollectibleChest          CollectibleKind = "chest"
+	CollectibleHeartPiece     CollectibleKind = "heart-piece"
+	CollectibleHeartContainer CollectibleKind = "heart-container"
+	CollectibleStrayFairy     CollectibleKind = "stray-fairy"
+	CollectibleGoldSkulltula  CollectibleKind = "gold-skulltula"
+	CollectibleRupeeCluster   CollectibleKind = "rupee-cluster"
+	CollectibleItem           CollectibleKind = "item"
+)
+
+// Collectible is an item placed in a room.
+type Collectible struct {
+	ActorOccurrence
+	Kind     CollectibleKind
+	ActorID  uint16
+	Name     string
+	Contents string `json:",omitempty"`
+
+	FlagType FlagType `json:",omitempty"`
+	Flag     uint16
+}
+
+// collectibleRule classifies an actor, params are decoded using
+// ActorParamSchemas and the flag is the field marked as such.
+type collectibleRule struct {
+	Kind     CollectibleKind
+	Contents string // params field holding the contents
+
+	// Type is the params field telling variants apart when only some of them
+	// are collectibles, Types lists their values.
+	Type  string
+	Types []uint16
+
+	// FlagType and Flag are the flag of actors that do not take it from their
+	// params.
+	FlagType FlagType
+	Flag     uint16
+}
+
+// actorItem00 is the ID of En_Item00, its type tells the collectible kind.
+const actorItem00 = 0x000E
+
+// En_Item00 item types that are not plain collectibles.
+const (
+	item00HeartPiece     = 0x06
+	item00HeartContainer = 0x07
+)
+
+// collectibleRules maps actor IDs to the collectible they place.
+var collectibleRules = map[uint16]collectibleRule{
+	0x0006:      {Kind: CollectibleChest, Contents: "item"},                               // En_Box
+	actorItem00: {Kind: CollectibleItem, Contents: "type"},                                // En_Item00
+	0x003A:      {Kind: CollectibleHeartContainer, FlagType: FlagCollectible, Flag: 0x1F}, // Item_B_Heart
+	0x0050:      {Kind: CollectibleGoldSkulltula, Type: "type", Types: []uint16{1, 2, 3}}, // En_Sw
+	0x00E3:      {Kind: CollectibleGoldSkulltula},                                         // En_Si
+	0x00E8:      {Kind: CollectibleRupeeCluster, Contents: "type"},                        // Obj_Mure3
+	0x01B0:      {Kind: CollectibleStrayFairy, Contents: "area"},                          // En_Elforg
+	0x01B1:      {Kind: CollectibleStrayFairy},                                            // En_Elfbub
+}
+
+// matches returns true if the actor is one of the variants of the rule.
+func (rule collectibleRule) matches(actor ActorEntry) bool {
+	if rule.Type == "" {
+		return true
+	}
+
+	param, ok := actor.Param(rule.Type)
+	if !ok {
+		return false
+	}
+
+	for _, v := range rule.Types {
+		if param.Value == v {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Param returns the decoded params field of the given name.
+func (a ActorEntry) Param(name string) (ActorParam, bool) {
+	for _, param := range a.Params {
+		if param.Name == name {
+			return param, true
+		}
+	}
+
+	return ActorParam{}, false
+}
+
+// FlagParams returns the decoded params fields holding a scene flag.
+func (a ActorEntry) FlagParams() []ActorParam {
+	params := make([]ActorParam, 0, 2)
+	for _, param := range a.Params {
+		if param.Flag != "" {
+			params = append(params, param)
+		}
+	}
+
+	return params
+}
+
+// Collectibles returns every collectible placed in the game, including in
+// alternate setups.
+func (v *View) Collectibles() []Collectible {
+	collectibles := make([]Collectible, 0, 1024)
+	v.eachActor(func(occurrence ActorOccurrence, actor ActorEntry) {
+		rule, ok := collectibleRules[actor.ID]
+		if !ok || !rule.matches(actor) {
+			return
+		}
+
+		c := Collectible{
+			ActorOccurrence: occurrence,
+			Kind:            rule.Kind,
+			ActorID:         actor.ID,
+			Name:            actor.Description.Identification,
+			FlagType:        rule.FlagType,
+			Flag:            rule.Flag,
+		}
+		if c.Name == "" {
+			c.Name = actor.Description.FileName
+		}
+
+		if param, ok := actor.Param(rule.Contents); ok {
+			c.Contents = param.String()
+			if param.ValueName != "" {
+				c.Contents = param.ValueName
+			}
+
+			if actor.ID == actorItem00 {
+				switch param.Value {
+				case item00HeartPiece:
+					c.Kind = CollectibleHeartPiece
+				case item00HeartContainer:
+					c.Kind = CollectibleHeartContainer
+				}
+			}
+		}
+
+		// The item flag comes first, clear flags only tell when it appears.
+		for _, param := range actor.FlagParams() {
+			if param.Flag != FlagClear {
+				c.FlagType, c.Flag = param.Flag, param.Value
+				break
+			}
+		}
+
+		collectibles = append(collectibles, c)
+	})
+
+	return collectibles
+}
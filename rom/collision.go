@@ -0,0 +1,122 @@
+package rom
+
+import (
+	"encoding/binary"
+	"io"
+)
+
+// CollisionHeader is the header pointed to by the 0x03 scene header command.
+// Sources:
+// - https://wiki.cloudmodding.com/oot/Scenes_and_Rooms#Collision
+// binpacked, do not change struct size
+type CollisionHeader struct {
+	MinBounds                 Vec3   // 0x00
+	MaxBounds                 Vec3   // 0x06
+	VerticesCount             uint16 // 0x0C
+	_                         uint16
+	VerticesSegmentOffset     uint32 // 0x10
+	PolygonsCount             uint16 // 0x14
+	_                         uint16
+	PolygonsSegmentOffset     uint32 // 0x18
+	SurfaceTypesSegmentOffset uint32 // 0x1C
+	CamerasSegmentOffset      uint32 // 0x20
+	WaterBoxesCount           uint16 // 0x24
+	_                         uint16
+	WaterBoxesSegmentOffset   uint32 // 0x28 - 0x2C
+}
+
+// CollisionPolygon is a single collision triangle.
+// binpacked, do not change struct size
+type CollisionPolygon struct {
+	Type     uint16 // index in Collision.SurfaceTypes
+	VertexA  uint16 // 3 MSB are flags
+	VertexB  uint16 // 3 MSB are flags
+	VertexC  uint16
+	Normal   Vec3
+	Distance int16
+}
+
+// Vertices returns the polygon vertex indices without their flags.
+func (p CollisionPolygon) Vertices() [3]uint16 {
+	return [3]uint16{
+		p.VertexA & 0x1FFF,
+		p.VertexB & 0x1FFF,
+		p.VertexC & 0x1FFF,
+	}
+}
+
+// SurfaceType holds the properties shared by polygons of the same type.
+// binpacked, do not change struct size
+type SurfaceType struct {
+	Data0 uint32
+	Data1 uint32
+}
+
+// CameraIndex returns the index of the bg camera used when standing on
+// polygons of this type.
+func (s SurfaceType) CameraIndex() byte {
+	return byte(s.Data0 & 0x000000FF)
+}
+
+// Collision holds a Scene collision mesh and its bg cameras.
+type Collision struct {
+	CollisionHeader
+
+	Vertices     []Vec3             `json:"-"`
+	Polygons     []CollisionPolygon `json:"-"`
+	SurfaceTypes []SurfaceType
+	Cameras      []Camera
+}
+
+func (c *Collision) load(r io.ReadSeeker, base uint32, headerSegmentOffset uint32) {
+	if headerSegmentOffset == 0 {
+		return
+	}
+
+	seekSegment(r, base, headerSegmentOffset)
+	binary.Read(r, binary.BigEndian, &c.CollisionHeader)
+
+	c.Vertices = make([]Vec3, c.VerticesCount, c.VerticesCount)
+	seekSegment(r, base, c.VerticesSegmentOffset)
+	binary.Read(r, binary.BigEndian, c.Vertices)
+
+	c.Polygons = make([]CollisionPolygon, c.PolygonsCount, c.PolygonsCount)
+	seekSegment(r, base, c.PolygonsSegmentOffset)
+	binary.Read(r, binary.BigEndian, c.Polygons)
+
+	// Neither surface types nor cameras have a count, infer them from
+	// the highest index referenced.
+	surfaceTypesCount := 0
+	for _, polygon := range c.Polygons {
+		if int(polygon.Type) >= surfaceTypesCount {
+			surfaceTypesCount = int(polygon.Type) + 1
+		}
+	}
+
+	c.SurfaceTypes = make([]SurfaceType, surfaceTypesCount, surfaceTypesCount)
+	seekSegment(r, base, c.SurfaceTypesSegmentOffset)
+	binary.Read(r, binary.BigEndian, c.SurfaceTypes)
+
+	if c.CamerasSegmentOffset == 0 {
+		return
+	}
+
+	camerasCount := 0
+	for _, surfaceType := range c.SurfaceTypes {
+		if int(surfaceType.CameraIndex()) >= camerasCount {
+			camerasCount = int(surfaceType.CameraIndex()) + 1
+		}
+	}
+
+	c.Cameras = loadCameras(r, base, c.CamerasSegmentOffset, camerasCount)
+	for k, polygon := range c.Polygons {
+		index := c.SurfaceTypes[polygon.Type].CameraIndex()
+		c.Cameras[index].Polygons = append(c.Cameras[index].Polygons, uint16(k))
+	}
+}
+
+// seekSegment seeks to a segmented address relative to the file starting at
+// base, the segment number itself is ignored.
+func seekSegment(r io.Seeker, base uint32, segmentOffset uint32) {
+	r.Seek(int64(base+(segmentOffset&0x00FFFFFF)), io.SeekStart)
+}
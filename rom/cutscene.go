@@ -0,0 +1,171 @@
+package rom
+
+import (
+	"encoding/binary"
+	"io"
+)
+
+// CutsceneEntry is an entry of the scene cutscene list (0x17 header command).
+// binpacked, do not change struct size
+type CutsceneEntry struct {
+	SegmentOffset uint32
+	NextEntrance  uint16
+	Spawn         uint8
+	SpawnFlags    uint8
+}
+
+// Cutscene is a scene cutscene, only its text commands are decoded.
+type Cutscene struct {
+	CutsceneEntry
+
+	Texts []CutsceneText
+}
+
+// CutsceneText displays a message during a cutscene.
+// binpacked, do not change struct size
+type CutsceneText struct {
+	TextID     uint16
+	StartFrame uint16
+	EndFrame   uint16
+	Type       uint16
+	AltTextID1 uint16 // choice branches
+	AltTextID2 uint16
+}
+
+// Cutscene command types, all commands not listed in cutsceneCommandSizes
+// are actor cues.
+const (
+	cutsceneCommandEnd          = 0xFFFFFFFF
+	cutsceneCommandText         = 0x83
+	cutsceneCommandCameraSpline = 0x5A // size is given in bytes instead of a count
+)
+
+// cutsceneTextNone is the TextID of CutsceneText that display nothing.
+const cutsceneTextNone = 0xFFFF
+
+// cutsceneTextOcarina is the CutsceneText.Type whose TextID is an ocarina
+// action instead of a message.
+const cutsceneTextOcarina = 0x02
+
+// cutsceneCommandSizes maps cutscene command types to the size of one of
+// their entries.
+var cutsceneCommandSizes = map[uint32]uint32{
+	0x96:                0x08, // misc
+	0x97:                0x08, // light setting
+	0x98:                0x08, // transition
+	0x99:                0x08, // motion blur
+	0x9A:                0x08, // give Tatl
+	0x9B:                0x0C, // general transition
+	0x9C:                0x08, // fade out sequence
+	0x9D:                0x08, // time
+	0x12C:               0x08, // start sequence
+	0x12D:               0x08, // stop sequence
+	0x12E:               0x08, // start ambience
+	0x12F:               0x08, // fade out ambience
+	0x130:               0x08, // sfx reverb
+	0x131:               0x08, // sfx reverb
+	0x132:               0x08, // modify sequence
+	0x15E:               0x08, // destination
+	0x15F:               0x08, // choose credits scenes
+	0x190:               0x0C, // rumble
+	cutsceneCommandText: uint32(binary.Size(CutsceneText{})),
+}
+
+// cutsceneActorCueSize is the size of an actor cue entry.
+const cutsceneActorCueSize = 0x30
+
+// maxCutsceneCommands bounds the command and entry counts of a cutscene, a
+// larger count means the data was misread.
+const maxCutsceneCommands = 0x400
+
+// MessageIDs returns the messages displayed by the text command.
+func (t CutsceneText) MessageIDs() []uint16 {
+	if t.Type == cutsceneTextOcarina {
+		return nil
+	}
+
+	ids := make([]uint16, 0, 3)
+	for _, id := range []uint16{t.TextID, t.AltTextID1, t.AltTextID2} {
+		if id != cutsceneTextNone && id != 0 {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids
+}
+
+func (s *Scene) loadCutscenes(r io.ReadSeeker) {
+	if s.CutscenesCount <= 0 {
+		return
+	}
+
+	entries := make([]CutsceneEntry, s.CutscenesCount, s.CutscenesCount)
+	seekSegment(r, s.VROMStart, s.CutscenesSegmentOffset)
+	binary.Read(r, binary.BigEndian, entries)
+
+	s.Cutscenes = make([]Cutscene, len(entries), len(entries))
+	for k, entry := range entries {
+		s.Cutscenes[k].CutsceneEntry = entry
+		if entry.SegmentOffset>>24 != sceneSegment {
+			continue
+		}
+
+		s.Cutscenes[k].Texts = s.loadCutsceneTexts(r, entry.SegmentOffset)
+	}
+}
+
+// loadCutsceneTexts walks the commands of a cutscene and returns its text
+// commands, it stops at the end command or when leaving the scene file.
+func (s *Scene) loadCutsceneTexts(r io.ReadSeeker, segmentOffset uint32) []CutsceneText {
+	texts := make([]CutsceneText, 0)
+	offset := s.VROMStart + segmentOffset&0x00FFFFFF
+
+	var header struct {
+		Commands int32
+		Frames   int32
+	}
+	r.Seek(int64(offset), io.SeekStart)
+	binary.Read(r, binary.BigEndian, &header)
+	offset += 8
+	if header.Commands < 0 || header.Commands > maxCutsceneCommands {
+		return texts
+	}
+
+	for i := int32(0); i < header.Commands && offset+8 <= s.VROMEnd; i++ {
+		var command struct {
+			Type  uint32
+			Count uint32
+		}
+		binary.Read(r, binary.BigEndian, &command)
+		offset += 8
+		if command.Type == cutsceneCommandEnd || command.Count > s.VROMEnd-offset {
+			break
+		}
+
+		var size uint32
+		switch command.Type {
+		case cutsceneCommandCameraSpline:
+			size = command.Count
+		case cutsceneCommandText:
+			if offset+command.Count*cutsceneCommandSizes[cutsceneCommandText] > s.VROMEnd {
+				return texts
+			}
+			entries := make([]CutsceneText, command.Count, command.Count)
+			binary.Read(r, binary.BigEndian, entries)
+			texts = append(texts, entries...)
+			offset += command.Count * cutsceneCommandSizes[cutsceneCommandText]
+			continue
+		default:
+			entrySize, ok := cutsceneCommandSizes[command.Type]
+			if !ok {
+				entrySize = cutsceneActorCueSize
+			}
+			size = command.Count * entrySize
+		}
+
+		offset += size
+		r.Seek(int64(offset), io.SeekStart)
+	}
+
+	return texts
+}
@@ -0,0 +1,123 @@
+package rom
+
+import (
+	"encoding/binary"
+	"io"
+)
+
+// Color is a RGB color
+// binpacked, do not change struct size
+type Color struct {
+	R byte
+	G byte
+	B byte
+}
+
+// Direction is a normalized direction vector, 0x7F being 1.0
+// binpacked, do not change struct size
+type Direction struct {
+	X int8
+	Y int8
+	Z int8
+}
+
+// EnvironmentSettingEntry is a single entry of the list pointed to by the 0x0F
+// header command, the game switches between them depending on the time of
+// day.
+// Sources:
+// - https://wiki.cloudmodding.com/mm/Scenes_and_Rooms#0x0F_Light_Settings
+// binpacked, do not change struct size
+type EnvironmentSettingEntry struct {
+	AmbientColor        Color     // 0x00
+	Light1Direction     Direction // 0x03
+	Light1Color         Color     // 0x06
+	Light2Direction     Direction // 0x09
+	Light2Color         Color     // 0x0C
+	FogColor            Color     // 0x0F
+	FogNearAndBlendRate uint16    // 0x12 0bxxxxxxyyyyyyyyyy x: blend rate, y: fog near
+	DrawDistance        uint16    // 0x14 - 0x16 aka. fog far
+}
+
+// EnvironmentSetting is an EnvironmentSettingEntry with its packed values
+// split.
+type EnvironmentSetting struct {
+	EnvironmentSettingEntry
+
+	FogNear   uint16 // distance at which the fog starts
+	BlendRate byte   // speed at which the game transitions to this setting
+}
+
+// Light is a single entry of the list pointed to by the 0x0C header command.
+// Position is only meaningful for point lights and Direction only for
+// directional lights.
+// binpacked, do not change struct size
+type Light struct {
+	Type     byte // 0x00 - 0x00: point, 0x01: directional, 0x02: point (glow)
+	_        byte
+	Position Vec3   // 0x02 directional lights store their direction here
+	Color    Color  // 0x08
+	DrawGlow byte   // 0x0B
+	Radius   uint16 // 0x0C - 0x0E
+}
+
+// Light types
+const (
+	LightTypePoint       = 0x00
+	LightTypeDirectional = 0x01
+	LightTypePointGlow   = 0x02
+)
+
+// Direction returns the light direction of a directional light.
+func (l Light) Direction() Direction {
+	return Direction{
+		X: int8(l.Position.X >> 8),
+		Y: int8(l.Position.X & 0xFF),
+		Z: int8(l.Position.Y >> 8),
+	}
+}
+
+// DirectionalColor returns the color of a directional light, it is stored
+// right after its direction instead of where point lights store theirs.
+func (l Light) DirectionalColor() Color {
+	return Color{
+		R: byte(l.Position.Y & 0xFF),
+		G: byte(l.Position.Z >> 8),
+		B: byte(l.Position.Z & 0xFF),
+	}
+}
+
+func (s *Scene) loadEnvironmentSettings(r io.ReadSeeker) {
+	s.EnvironmentSettings = make([]EnvironmentSetting, s.EnvironmentSettingsCount, s.EnvironmentSettingsCount)
+	if s.EnvironmentSettingsCount > 0 {
+		seekSegment(r, s.VROMStart, s.EnvironmentSettingsSegmentOffset)
+		for k := range s.EnvironmentSettings {
+			setting := &s.EnvironmentSettings[k]
+			binary.Read(r, binary.BigEndian, &setting.EnvironmentSettingEntry)
+			setting.FogNear = setting.FogNearAndBlendRate & 0x03FF
+			setting.BlendRate = byte((setting.FogNearAndBlendRate & 0xFC00) >> 10)
+		}
+	}
+
+	s.Lights = loadLights(r, s.VROMStart, s.LightSettingsSegmentOffset, s.LightSettingsCount)
+}
+
+// loadLights reads a light list (0x0C header command), scenes and rooms both
+// have one.
+func loadLights(r io.ReadSeeker, base, segOff uint32, count byte) []Light {
+	lights := make([]Light, count, count)
+	if count > 0 {
+		seekSegment(r, base, segOff)
+		binary.Read(r, binary.BigEndian, lights)
+	}
+
+	return lights
+}
+
+// LightColor returns the light color whatever its type.
+func (l Light) LightColor() Color {
+	if l.Type == LightTypeDirectional {
+		return l.DirectionalColor()
+	}
+
+	return l.Color
+}
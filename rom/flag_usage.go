@@ -0,0 +1,112 @@
+package rom
+
+import "sort"
+
+// FlagUser is an actor using a scene flag.
+type FlagUser struct {
+	ActorOccurrence
+	ActorID    uint16
+	Name       string
+	Access     FlagAccess `json:",omitempty"`
+	Transition bool       // true for transition actors, RoomID is their front room
+}
+
+// FlagUsage lists the actors using a scene flag.
+type FlagUsage struct {
+	Type  FlagType
+	Flag  uint16
+	Users []FlagUser
+
+	// Conflict is true when unrelated actors share the flag, see conflicts.
+	Conflict bool
+}
+
+// SceneFlags lists every flag used by the actors of a scene, including
+// transition actors and alternate setups, sorted by type and flag.
+func (v *View) SceneFlags(scene *Scene) []FlagUsage {
+	usages := make(map[FlagType]map[uint16][]FlagUser, 4)
+	add := func(actor ActorEntry, user FlagUser) {
+		user.ActorID = actor.ID
+		user.Name = actor.Description.Identification
+		if user.Name == "" {
+			user.Name = actor.Description.FileName
+		}
+
+		for _, param := range actor.FlagParams() {
+			user.Access = param.Access
+			flag := param.FlagValue(user.RoomID)
+			if usages[param.Flag] == nil {
+				usages[param.Flag] = make(map[uint16][]FlagUser)
+			}
+			usages[param.Flag][flag] = append(usages[param.Flag][flag], user)
+		}
+	}
+
+	scene.eachActor(func(occurrence ActorOccurrence, actor ActorEntry) {
+		add(actor, FlagUser{ActorOccurrence: occurrence})
+	})
+
+	for k, transition := range scene.TransitionActors {
+		actor := ActorEntry{
+			ID:             transition.ID & transitionActorIDMask,
+			Initialization: transition.Initialization,
+			Position:       transition.Position,
+			Description:    transition.Description,
+			Params:         transition.Params,
+		}
+
+		add(actor, FlagUser{
+			ActorOccurrence: ActorOccurrence{
+				SceneVROMStart:    scene.VROMStart,
+				SceneName:         scene.Name,
+				RoomID:            byte(transition.FrontRoom),
+				Index:             k,
+				Position:          transition.Position,
+				Initialization:    transition.Initialization,
+				ParamsDescription: transition.ParamsDescription,
+			},
+			Transition: true,
+		})
+	}
+
+	list := make([]FlagUsage, 0, 64)
+	for typ, flags := range usages {
+		for flag, users := range flags {
+			list = append(list, FlagUsage{
+				Type:     typ,
+				Flag:     flag,
+				Users:    users,
+				Conflict: conflicts(typ, users),
+			})
+		}
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Type != list[j].Type {
+			return list[i].Type < list[j].Type
+		}
+		return list[i].Flag < list[j].Flag
+	})
+
+	return list
+}
+
+// conflicts returns true if unrelated actors share a flag. Actors with the same
+// ID and params are the same actor placed in many setups or rooms, others are
+// unrelated. Chest and collectible flags belong to a single item so any two
+// unrelated users conflict, switch and clear flags are meant to be shared
+// between an actor setting them and others testing them so only unrelated
+// setters conflict.
+func conflicts(typ FlagType, users []FlagUser) bool {
+	type key struct{ id, params uint16 }
+	owners := make(map[key]struct{}, len(users))
+
+	for _, user := range users {
+		if (typ == FlagSwitch || typ == FlagClear) && user.Access != FlagSet {
+			continue
+		}
+		owners[key{user.ActorID, user.Initialization}] = struct{}{}
+	}
+
+	return len(owners) > 1
+}
@@ -0,0 +1,21 @@
+package rom
+
+// FlagType is the kind of per-scene flag an actor uses to remember its state.
+type FlagType string
+
+// Flag types
+const (
+	FlagSwitch      FlagType = "switch"
+	FlagChest       FlagType = "chest"
+	FlagCollectible FlagType = "collectible"
+	FlagClear       FlagType = "clear" // set when all enemies of a room are defeated
+)
+
+// FlagAccess tells whether an actor sets or tests a flag.
+type FlagAccess string
+
+// Flag accesses, an empty access means unknown.
+const (
+	FlagSet  FlagAccess = "set"
+	FlagTest FlagAccess = "test"
+)
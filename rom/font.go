@@ -0,0 +1,78 @@
+package rom
+
+import (
+	"log"
+	"math"
+)
+
+// FontWidths holds the width of each font character in pixels, at the
+// font native scale, starting at ' '.
+type FontWidths []float32
+
+// fontWidthsFirstCharacter is the character the width table starts at.
+const fontWidthsFirstCharacter = 0x20
+
+// The table is ROM.FontWidths, widths of the printable ASCII range are
+// checked to catch a wrong offset.
+const (
+	minFontWidthsLength = 0x5F // printable ASCII range
+	maxFontWidth        = 32
+)
+
+// Font glyphs are 16x16 I4 textures starting at ' ' in nes_font_static.
+const (
+	FontGlyphSide = 16
+	FontGlyphSize = FontGlyphSide * FontGlyphSide / 2
+)
+
+// Width returns the width of a character, 0 if unknown.
+func (f FontWidths) Width(b byte) float32 {
+	if b < fontWidthsFirstCharacter || int(b-fontWidthsFirstCharacter) >= len(f) {
+		return 0
+	}
+
+	return f[b-fontWidthsFirstCharacter]
+}
+
+// Has returns true if the table has the width of the given character.
+func (f FontWidths) Has(b byte) bool {
+	return b >= fontWidthsFirstCharacter && int(b-fontWidthsFirstCharacter) < len(f)
+}
+
+func (v *View) loadFontWidths() {
+	widths := FontWidths(v.rom.FontWidths[:])
+	for _, w := range widths[:minFontWidthsLength] {
+		if w < 1 || w > maxFontWidth || w != float32(math.Trunc(float64(w))) {
+			log.Print("Font widths table is invalid")
+			return
+		}
+	}
+
+	v.FontWidths = widths
+	log.Printf("Loaded %d font widths", len(widths))
+}
+
+// Glyph returns the I4 texture of a character, nil if not in the font.
+func (v *View) Glyph(b byte) []byte {
+	if b < fontWidthsFirstCharacter {
+		return nil
+	}
+
+	start := int(b-fontWidthsFirstCharacter) * FontGlyphSize
+	if start+FontGlyphSize > len(v.FontGlyphs) {
+		return nil
+	}
+
+	return v.FontGlyphs[start : start+FontGlyphSize]
+}
+
+func (v *View) loadFontGlyphs() {
+	file, err := v.GetFileByName("nes_font_static")
+	if err != nil || file.Size()%FontGlyphSize != 0 {
+		log.Print("Font glyphs not found")
+		return
+	}
+
+	v.FontGlyphs = file.data
+	log.Printf("Loaded %d font glyphs", file.Size()/FontGlyphSize)
+}
@@ -0,0 +1,230 @@
+package rom
+
+// HeaderValues holds the values decoded from a single header command, keyed by
+// the name of the LocationHeader field they are stored in.
+type HeaderValues map[string]interface{}
+
+// A HeaderCommandDecoder interprets the two words of a header command, stores
+// the result in the LocationHeader named fields and returns what it decoded.
+type HeaderCommandDecoder struct {
+	Name   string
+	Decode func(l *LocationHeader, a, b uint32) HeaderValues
+}
+
+// HeaderCommandDecoders maps a header command byte to its decoder, commands
+// without a decoder are kept raw in LocationHeader.Commands.
+// Sources:
+// - https://wiki.cloudmodding.com/mm/Scenes_and_Rooms#Header_Commands
+var HeaderCommandDecoders = map[byte]HeaderCommandDecoder{
+	0x00: {"StartPositions", func(l *LocationHeader, a, b uint32) HeaderValues {
+		l.StartPositionsCount = headerCount(a)
+		l.StartPositionsSegmentOffset = b
+		return HeaderValues{
+			"StartPositionsCount":         l.StartPositionsCount,
+			"StartPositionsSegmentOffset": b,
+		}
+	}},
+	0x01: {"Actors", func(l *LocationHeader, a, b uint32) HeaderValues {
+		l.ActorsCount = headerCount(a)
+		l.ActorsSegmentOffset = b
+		return HeaderValues{
+			"ActorsCount":         l.ActorsCount,
+			"ActorsSegmentOffset": b,
+		}
+	}},
+	0x02: {"Cameras", func(l *LocationHeader, a, b uint32) HeaderValues {
+		l.CamerasCount = headerCount(a)
+		l.CamerasSegmentOffset = b
+		return HeaderValues{
+			"CamerasCount":         l.CamerasCount,
+			"CamerasSegmentOffset": b,
+		}
+	}},
+	0x03: {"CollisionHeader", func(l *LocationHeader, a, b uint32) HeaderValues {
+		l.CollisionHeaderSegmentOffset = b
+		return HeaderValues{"CollisionHeaderSegmentOffset": b}
+	}},
+	0x04: {"Rooms", func(l *LocationHeader, a, b uint32) HeaderValues {
+		l.RoomsCount = headerCount(a)
+		l.RoomsSegmentOffset = b
+		return HeaderValues{
+			"RoomsCount":         l.RoomsCount,
+			"RoomsSegmentOffset": b,
+		}
+	}},
+	0x05: {"Wind", func(l *LocationHeader, a, b uint32) HeaderValues {
+		l.WindDirectionX = byte((b & 0xFF000000) >> 24)
+		l.WindDirectionY = byte((b & 0x00FF0000) >> 16)
+		l.WindDirectionZ = byte((b & 0x0000FF00) >> 8)
+		l.WindStrength = byte(b & 0x000000FF)
+		return HeaderValues{
+			"WindDirectionX": l.WindDirectionX,
+			"WindDirectionY": l.WindDirectionY,
+			"WindDirectionZ": l.WindDirectionZ,
+			"WindStrength":   l.WindStrength,
+		}
+	}},
+	0x06: {"Entrances", func(l *LocationHeader, a, b uint32) HeaderValues {
+		l.EntrancesCount = headerCount(a)
+		l.EntrancesSegmentOffset = b
+		return HeaderValues{
+			"EntrancesCount":         l.EntrancesCount,
+			"EntrancesSegmentOffset": b,
+		}
+	}},
+	0x07: {"SpecialObjects", func(l *LocationHeader, a, b uint32) HeaderValues {
+		l.SpecialObjectsByte0 = headerCount(a)
+		l.SpecialObjects = uint16(b & 0x0000FFFF)
+		return HeaderValues{
+			"SpecialObjectsByte0": l.SpecialObjectsByte0,
+			"SpecialObjects":      l.SpecialObjects,
+		}
+	}},
+	0x08: {"RoomBehavior", func(l *LocationHeader, a, b uint32) HeaderValues {
+		l.RoomBehavior0 = headerCount(a)
+		l.RoomBehavior1 = byte((b & 0x0000FF00) >> 8)
+		l.RoomBehavior2 = byte(b & 0x000000FF)
+		return HeaderValues{
+			"RoomBehavior0": l.RoomBehavior0,
+			"RoomBehavior1": l.RoomBehavior1,
+			"RoomBehavior2": l.RoomBehavior2,
+		}
+	}},
+	// "Has two instructions saving values to the stack, which aren't read before being overwritten."
+	0x09: {"Unused", func(l *LocationHeader, a, b uint32) HeaderValues {
+		return nil
+	}},
+	0x0A: {"Mesh", func(l *LocationHeader, a, b uint32) HeaderValues {
+		l.MeshSegmentOffset = b
+		return HeaderValues{"MeshSegmentOffset": b}
+	}},
+	0x0B: {"Objects", func(l *LocationHeader, a, b uint32) HeaderValues {
+		l.ObjectsCount = headerCount(a)
+		l.ObjectsSegmentOffset = b
+		return HeaderValues{
+			"ObjectsCount":         l.ObjectsCount,
+			"ObjectsSegmentOffset": b,
+		}
+	}},
+	0x0C: {"LightSettings", func(l *LocationHeader, a, b uint32) HeaderValues {
+		l.LightSettingsCount = headerCount(a)
+		l.LightSettingsSegmentOffset = b
+		return HeaderValues{
+			"LightSettingsCount":         l.LightSettingsCount,
+			"LightSettingsSegmentOffset": b,
+		}
+	}},
+	0x0D: {"Paths", func(l *LocationHeader, a, b uint32) HeaderValues {
+		l.PathsSegmentOffset = b
+		return HeaderValues{"PathsSegmentOffset": b}
+	}},
+	0x0E: {"ActorTransitions", func(l *LocationHeader, a, b uint32) HeaderValues {
+		l.ActorTransitionsCount = headerCount(a)
+		l.ActorTransitionsSegmentOffset = b
+		return HeaderValues{
+			"ActorTransitionsCount":         l.ActorTransitionsCount,
+			"ActorTransitionsSegmentOffset": b,
+		}
+	}},
+	0x0F: {"EnvironmentSettings", func(l *LocationHeader, a, b uint32) HeaderValues {
+		l.EnvironmentSettingsCount = headerCount(a)
+		l.EnvironmentSettingsSegmentOffset = b
+		return HeaderValues{
+			"EnvironmentSettingsCount":         l.EnvironmentSettingsCount,
+			"EnvironmentSettingsSegmentOffset": b,
+		}
+	}},
+	0x10: {"Time", func(l *LocationHeader, a, b uint32) HeaderValues {
+		l.TimeStart = uint16((b & 0xFFFF0000) >> 16)
+		l.TimeSpeed = byte((b & 0x0000FF00) >> 8)
+		return HeaderValues{
+			"TimeStart": l.TimeStart,
+			"TimeSpeed": l.TimeSpeed,
+		}
+	}},
+	0x11: {"Skybox", func(l *LocationHeader, a, b uint32) HeaderValues {
+		l.SkyboxNumber = byte((b & 0xFF000000) >> 24)
+		l.SkyboxCast = byte((b & 0x000F0000) >> 16)
+		l.SkyboxFog = byte((b & 0x00000F00) >> 8)
+		return HeaderValues{
+			"SkyboxNumber": l.SkyboxNumber,
+			"SkyboxCast":   l.SkyboxCast,
+			"SkyboxFog":    l.SkyboxFog,
+		}
+	}},
+	0x12: {"SkyboxModifier", func(l *LocationHeader, a, b uint32) HeaderValues {
+		l.SkyboxDisable = (b & 0xFF000000) > 0
+		l.SkyboxModifier = byte((b & 0x00FF0000) >> 16)
+		return HeaderValues{
+			"SkyboxDisable":  l.SkyboxDisable,
+			"SkyboxModifier": l.SkyboxModifier,
+		}
+	}},
+	0x13: {"Exits", func(l *LocationHeader, a, b uint32) HeaderValues {
+		l.ExitsSegmentOffset = b
+		return HeaderValues{"ExitsSegmentOffset": b}
+	}},
+	0x14: {"End", func(l *LocationHeader, a, b uint32) HeaderValues {
+		return nil
+	}},
+	0x15: {"Sound", func(l *LocationHeader, a, b uint32) HeaderValues {
+		l.SoundReverb = headerCount(a)
+		l.SoundNightSFX = byte((b & 0x0000FF00) >> 8)
+		l.SoundBackgroundSequence = byte(b & 0x000000FF)
+		return HeaderValues{
+			"SoundReverb":             l.SoundReverb,
+			"SoundNightSFX":           l.SoundNightSFX,
+			"SoundBackgroundSequence": l.SoundBackgroundSequence,
+		}
+	}},
+	0x16: {"SoundEcho", func(l *LocationHeader, a, b uint32) HeaderValues {
+		l.SoundEcho = byte(b & 0x000000FF)
+		return HeaderValues{"SoundEcho": l.SoundEcho}
+	}},
+	0x17: {"Cutscenes", func(l *LocationHeader, a, b uint32) HeaderValues {
+		l.CutscenesCount = headerCount(a)
+		l.CutscenesSegmentOffset = b
+		return HeaderValues{
+			"CutscenesCount":         l.CutscenesCount,
+			"CutscenesSegmentOffset": b,
+		}
+	}},
+	0x18: {"AlternateHeaders", func(l *LocationHeader, a, b uint32) HeaderValues {
+		l.AlternateHeadersSegmentOffset = b
+		return HeaderValues{"AlternateHeadersSegmentOffset": b}
+	}},
+	0x19: {"WorldMapLocation", func(l *LocationHeader, a, b uint32) HeaderValues {
+		l.IsWorldMapLocation = true
+		return HeaderValues{"IsWorldMapLocation": true}
+	}},
+	0x1A: {"TextureAnimations", func(l *LocationHeader, a, b uint32) HeaderValues {
+		l.TextureAnimationsSegmentOffset = b
+		return HeaderValues{"TextureAnimationsSegmentOffset": b}
+	}},
+	0x1B: {"CamerasAndCutscenesForActors", func(l *LocationHeader, a, b uint32) HeaderValues {
+		l.CamerasAndCutscenesForActorsCount = headerCount(a)
+		l.CamerasAndCutscenesForActorsSegmentOffset = b
+		return HeaderValues{
+			"CamerasAndCutscenesForActorsCount":         l.CamerasAndCutscenesForActorsCount,
+			"CamerasAndCutscenesForActorsSegmentOffset": b,
+		}
+	}},
+	0x1C: {"Minimaps", func(l *LocationHeader, a, b uint32) HeaderValues {
+		l.MinimapsSegmentOffset = b
+		return HeaderValues{"MinimapsSegmentOffset": b}
+	}},
+	0x1E: {"MapChestPositions", func(l *LocationHeader, a, b uint32) HeaderValues {
+		l.MapChestPositionsCount = headerCount(a)
+		l.MapChestPositionsSegmentOffset = b
+		return HeaderValues{
+			"MapChestPositionsCount":         l.MapChestPositionsCount,
+			"MapChestPositionsSegmentOffset": b,
+		}
+	}},
+}
+
+// headerCount returns the second byte of the first header word, most
+// commands store their item count there.
+func headerCount(a uint32) byte {
+	return byte((a & 0x00FF0000) >> 16)
+}
@@ -11,6 +11,12 @@ func init() {
 	if err := initLoadActors(data); err != nil {
 		panic(err)
 	}
+	if err := initLoadMessageRanges(data); err != nil {
+		panic(err)
+	}
+	if err := initLoadActorParamSchemas(data); err != nil {
+		panic(err)
+	}
 }
 
 func initLoadActors(data packr.Box) error {
@@ -30,3 +36,21 @@ func initLoadActors(data packr.Box) error {
 
 	return nil
 }
+
+func initLoadMessageRanges(data packr.Box) error {
+	ranges, err := data.Find("message_ranges.json")
+	if err != nil {
+		return err
+	}
+
+	return loadMessageRanges(ranges)
+}
+
+func initLoadActorParamSchemas(data packr.Box) error {
+	schemas, err := data.Find("actor_params.json")
+	if err != nil {
+		return err
+	}
+
+	return LoadActorParamSchemas(schemas)
+}
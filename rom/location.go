@@ -2,9 +2,7 @@ package rom
 
 import (
 	"encoding/binary"
-	"fmt"
 	"io"
-	"log"
 )
 
 // LocationHeader holds the of both Scenes and Rooms as many of them are shared.
@@ -72,6 +70,23 @@ type LocationHeader struct {
 	MinimapsSegmentOffset                     uint32 // 0x1C000000 0xxxxxxxxx *
 	MapChestPositionsCount                    byte   // 0x1Exx0000
 	MapChestPositionsSegmentOffset            uint32 // 0xyyyyyyyy
+
+	// Commands holds every header command in the order they were found,
+	// including duplicates and undocumented ones that the named fields above
+	// cannot represent.
+	Commands []HeaderCommand
+}
+
+// HeaderCommand is a single header command as found in the ROM, along with
+// the values its decoder extracted from it.
+type HeaderCommand struct {
+	Offset  uint32 // ROM offset of the command
+	Command byte
+	Name    string // empty if the command is not documented
+	A       uint32 // first word, including the command byte
+	B       uint32 // second word
+
+	Values HeaderValues
 }
 
 // Returns offset after reading header (actual data start)
@@ -83,116 +98,33 @@ func (l *LocationHeader) load(r io.ReadSeeker, start uint32) uint32 {
 	for {
 		binary.Read(r, binary.BigEndian, &a)
 		binary.Read(r, binary.BigEndian, &b)
+
+		command := l.loadHeader(offset, a, b)
 		offset += 8
 
-		command := byte((a & 0xFF000000) >> 24)
-		if command == sceneHeaderEndCommand {
+		if command.Command == sceneHeaderEndCommand {
 			break
 		}
-
-		if err := l.loadHeader(command, a, b); err != nil {
-			log.Printf(
-				"ERROR at offset 0x%08X: %s",
-				offset-8,
-				err,
-			)
-		}
 	}
 
 	return offset
 }
 
-func (l *LocationHeader) loadHeader(command byte, a uint32, b uint32) error {
-	switch command {
-	case 0x14:
-		return fmt.Errorf("loadHeader does not handle the header end command")
-	default:
-		// There's loads of Room headers that are not documented yet so silence the warning for now
-		// TODO: understand them, maybe
-		// return fmt.Errorf("unknown LocationHeader command 0x%02X (0x%08X 0x%08X)", command, a, b)
-	case 0x00:
-		l.StartPositionsCount = byte((a & 0x00FF0000) >> 16)
-		l.StartPositionsSegmentOffset = b
-	case 0x01:
-		l.ActorsCount = byte((a & 0x00FF0000) >> 16)
-		l.ActorsSegmentOffset = b
-	case 0x02:
-		l.CamerasCount = byte((a & 0x00FF0000) >> 16)
-		l.CamerasSegmentOffset = b
-	case 0x03:
-		l.CollisionHeaderSegmentOffset = b
-	case 0x04:
-		l.RoomsCount = byte((a & 0x00FF0000) >> 16)
-		l.RoomsSegmentOffset = b
-	case 0x05:
-		l.WindDirectionX = byte((b & 0xFF000000) >> 24)
-		l.WindDirectionY = byte((b & 0x00FF0000) >> 16)
-		l.WindDirectionZ = byte((b & 0x0000FF00) >> 8)
-		l.WindStrength = byte(b & 0x000000FF)
-	case 0x06:
-		l.EntrancesCount = byte((a & 0x00FF0000) >> 16)
-		l.EntrancesSegmentOffset = b
-	case 0x07:
-		l.SpecialObjectsByte0 = byte((a & 0x00FF0000) >> 16)
-		l.SpecialObjects = uint16(b & 0x0000FFFF)
-	case 0x08:
-		l.RoomBehavior0 = byte((a & 0x00FF0000) >> 16)
-		l.RoomBehavior1 = byte((b & 0x0000FF00) >> 8)
-		l.RoomBehavior2 = byte(b & 0x000000FF)
-	case 0x09:
-		// "Has two instructions saving values to the stack, which aren't read before being overwritten."
-	case 0x0A:
-		l.MeshSegmentOffset = b
-	case 0x0B:
-		l.ObjectsCount = byte((a & 0x00FF0000) >> 16)
-		l.ObjectsSegmentOffset = b
-	case 0x0C:
-		l.LightSettingsCount = byte((a & 0x00FF0000) >> 16)
-		l.LightSettingsSegmentOffset = b
-	case 0x0D:
-		l.PathsSegmentOffset = b
-	case 0x0E:
-		l.ActorTransitionsCount = byte((a & 0x00FF0000) >> 16)
-		l.ActorTransitionsSegmentOffset = b
-	case 0x0F:
-		l.EnvironmentSettingsCount = byte((a & 0x00FF0000) >> 16)
-		l.EnvironmentSettingsSegmentOffset = b
-	case 0x10:
-		l.TimeStart = uint16((b & 0xFFFF0000) >> 16)
-		l.TimeSpeed = byte((b & 0x0000FF00) >> 8)
-	case 0x11:
-		l.SkyboxNumber = byte((b & 0xFF000000) >> 24)
-		l.SkyboxCast = byte((b & 0x000F0000) >> 16)
-		l.SkyboxFog = byte((b & 0x00000F00) >> 8)
-	case 0x12:
-		l.SkyboxDisable = (b & 0xFF000000) > 0
-		l.SkyboxModifier = byte((b & 0x00FF0000) >> 16)
-	case 0x13:
-		l.ExitsSegmentOffset = b
-	case 0x15:
-		l.SoundReverb = byte((a & 0x00FF0000) >> 16)
-		l.SoundNightSFX = byte((b & 0x0000FF00) >> 8)
-		l.SoundBackgroundSequence = byte(b & 0x000000FF)
-	case 0x16:
-		l.SoundEcho = byte(b & 0x000000FF)
-	case 0x17:
-		l.CutscenesCount = byte((a & 0x00FF0000) >> 16)
-		l.CutscenesSegmentOffset = b
-	case 0x18:
-		l.AlternateHeadersSegmentOffset = b
-	case 0x19:
-		l.IsWorldMapLocation = true
-	case 0x1A:
-		l.TextureAnimationsSegmentOffset = b
-	case 0x1B:
-		l.CamerasAndCutscenesForActorsCount = byte((a & 0x00FF0000) >> 16)
-		l.CamerasAndCutscenesForActorsSegmentOffset = b
-	case 0x1C:
-		l.MinimapsSegmentOffset = b
-	case 0x1E:
-		l.MapChestPositionsCount = byte((a & 0x00FF0000) >> 16)
-		l.MapChestPositionsSegmentOffset = b
+// loadHeader decodes a single header command and appends it to Commands.
+func (l *LocationHeader) loadHeader(offset uint32, a uint32, b uint32) HeaderCommand {
+	command := HeaderCommand{
+		Offset:  offset,
+		Command: byte((a & 0xFF000000) >> 24),
+		A:       a,
+		B:       b,
 	}
 
-	return nil
+	if decoder, ok := HeaderCommandDecoders[command.Command]; ok {
+		command.Name = decoder.Name
+		command.Values = decoder.Decode(l, a, b)
+	}
+
+	l.Commands = append(l.Commands, command)
+
+	return command
 }
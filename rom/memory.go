@@ -0,0 +1,232 @@
+package rom
+
+import (
+	"fmt"
+	"sort"
+)
+
+// MemoryLimits are the sizes a room's contents must fit in. They vary per
+// scene, see SceneMemoryLimits.
+type MemoryLimits struct {
+	ActorHeap   uint32  // arena holding actor overlays and instances
+	ObjectSpace uint32  // object bank
+	Threshold   float64 // ratio of a limit above which a room is flagged
+}
+
+// DefaultMemoryLimits are used when no limits are given, the actor heap size
+// is a rough estimate meant to spot outliers.
+var DefaultMemoryLimits = MemoryLimits{
+	ActorHeap:   0x00100000,
+	ObjectSpace: 1413 * 1024,
+	Threshold:   0.9,
+}
+
+// sceneObjectSpaceSizes maps scene names to the object space the game
+// allocates for them when it differs from the default.
+var sceneObjectSpaceSizes = map[string]uint32{
+	"Z2_CLOCKTOWER": 1566 * 1024,
+	"Z2_TOWN":       1566 * 1024,
+	"Z2_BACKTOWN":   1566 * 1024,
+	"Z2_ICHIBA":     1566 * 1024,
+	"Z2_MILK_BAR":   1177 * 1024,
+	"Z2_00KEIKOKU":  1382 * 1024,
+}
+
+// SceneMemoryLimits returns DefaultMemoryLimits with the object space of the
+// given scene.
+func SceneMemoryLimits(scene *Scene) MemoryLimits {
+	limits := DefaultMemoryLimits
+	if size, ok := sceneObjectSpaceSizes[scene.Name]; ok {
+		limits.ObjectSpace = size
+	}
+
+	return limits
+}
+
+// arenaNodeSize is the header preceding each arena allocation.
+const arenaNodeSize = 0x10
+
+// Objects that are always loaded besides the room objects.
+const objectGameplayKeep = 0x0001
+
+// MemoryUsage is the estimated memory used by one or more loaded rooms.
+type MemoryUsage struct {
+	SceneVROMStart uint32
+	RoomIDs        []byte
+	Setup          int // see RoomSetup.Index
+
+	Overlays  uint32 // distinct actor overlays using the actor heap
+	Instances uint32 // actor instances, arena headers included
+	ActorHeap uint32 // Overlays + Instances
+	Objects   uint32 // distinct object files
+
+	ActorCount  int
+	ObjectIDs   []uint16
+	UnknownSize []uint16 `json:",omitempty"` // actor IDs whose instance size is unknown
+
+	Warnings []string `json:",omitempty"`
+}
+
+// SceneMemory holds the memory estimates of each room of a scene and of each
+// pair of rooms loaded together during a transition.
+type SceneMemory struct {
+	Limits      MemoryLimits
+	Rooms       []MemoryUsage
+	Transitions []MemoryUsage
+}
+
+// SceneMemory estimates the memory used by the rooms of the scene, alone and
+// by pairs linked by a transition actor. Estimates only account for the
+// actors and objects listed in the scene and room files, actors spawned at
+// runtime and allocations made by actors are not known.
+func (v *View) SceneMemory(scene *Scene, limits MemoryLimits) SceneMemory {
+	ret := SceneMemory{
+		Limits:      limits,
+		Rooms:       make([]MemoryUsage, 0, len(scene.Rooms)),
+		Transitions: make([]MemoryUsage, 0, len(scene.TransitionActors)),
+	}
+
+	for k := range scene.Rooms {
+		room := &scene.Rooms[k]
+		ret.Rooms = append(ret.Rooms, v.roomsMemory(scene, limits, 0, room))
+		for _, setup := range room.AlternateSetups {
+			if setup.HeaderSegmentOffset != 0 {
+				ret.Rooms = append(ret.Rooms, v.roomsMemory(scene, limits, setup.Index, room))
+			}
+		}
+	}
+
+	seen := make(map[[2]int8]bool)
+	for _, actor := range scene.TransitionActors {
+		a, b := actor.FrontRoom, actor.BackRoom
+		if a > b {
+			a, b = b, a
+		}
+
+		if a < 0 || a == b || int(b) >= len(scene.Rooms) || seen[[2]int8{a, b}] {
+			continue
+		}
+		seen[[2]int8{a, b}] = true
+
+		ret.Transitions = append(ret.Transitions, v.roomsMemory(scene, limits, 0, &scene.Rooms[a], &scene.Rooms[b]))
+	}
+
+	return ret
+}
+
+// roomsMemory estimates the memory used by rooms loaded together using the
+// given setup.
+func (v *View) roomsMemory(scene *Scene, limits MemoryLimits, setup int, rooms ...*Room) MemoryUsage {
+	usage := MemoryUsage{
+		SceneVROMStart: scene.VROMStart,
+		RoomIDs:        make([]byte, 0, len(rooms)),
+		Setup:          setup,
+	}
+
+	overlays := make(map[uint16]bool)
+	unknown := make(map[uint16]bool)
+	addActor := func(id uint16) {
+		usage.ActorCount++
+
+		size := v.InstanceSize(id)
+		if size == 0 {
+			unknown[id] = true
+		} else {
+			usage.Instances += size + arenaNodeSize
+		}
+
+		if overlays[id] || int(id) >= len(v.ActorOverlays) {
+			return
+		}
+		overlays[id] = true
+
+		overlay := v.ActorOverlays[id]
+		if overlay.AllocType == ActorAllocNormal && overlay.Size() > 0 {
+			usage.Overlays += overlay.Size() + arenaNodeSize
+		}
+	}
+
+	objects := map[uint16]bool{objectGameplayKeep: true}
+	if scene.SpecialObjects != 0 {
+		objects[scene.SpecialObjects] = true
+	}
+
+	// A transition actor links two rooms, it is only spawned once when both
+	// are loaded.
+	transitions := make(map[int]bool)
+	for _, room := range rooms {
+		usage.RoomIDs = append(usage.RoomIDs, room.ID)
+		actors, objectList := roomSetupLists(room, setup)
+		for _, actor := range actors {
+			addActor(actor.ID)
+		}
+		for _, id := range objectList {
+			objects[id] = true
+		}
+
+		for k, actor := range scene.TransitionActors {
+			if transitions[k] || (actor.FrontRoom != int8(room.ID) && actor.BackRoom != int8(room.ID)) {
+				continue
+			}
+			transitions[k] = true
+			addActor(actor.ID & transitionActorIDMask)
+		}
+	}
+
+	usage.ActorHeap = usage.Overlays + usage.Instances
+	usage.ObjectIDs = sortedIDs(objects)
+	usage.UnknownSize = sortedIDs(unknown)
+	for _, id := range usage.ObjectIDs {
+		if int(id) < len(v.Objects) {
+			usage.Objects += v.Objects[id].Size()
+		}
+	}
+
+	usage.Warnings = limits.check(usage)
+
+	return usage
+}
+
+// roomSetupLists returns the actors and objects of a room for the given setup,
+// falling back to the main header lists.
+func roomSetupLists(room *Room, setup int) ([]ActorEntry, []uint16) {
+	for _, s := range room.AlternateSetups {
+		if s.Index == setup && s.HeaderSegmentOffset != 0 {
+			return s.ActorList, s.ObjectList
+		}
+	}
+
+	return room.ActorList, room.ObjectList
+}
+
+func (l MemoryLimits) check(usage MemoryUsage) []string {
+	var warnings []string
+	check := func(name string, used, limit uint32) {
+		if limit == 0 {
+			return
+		}
+
+		ratio := float64(used) / float64(limit)
+		switch {
+		case used > limit:
+			warnings = append(warnings, fmt.Sprintf("%s over limit: 0x%X/0x%X (%.0f%%)", name, used, limit, ratio*100))
+		case ratio >= l.Threshold:
+			warnings = append(warnings, fmt.Sprintf("%s close to limit: 0x%X/0x%X (%.0f%%)", name, used, limit, ratio*100))
+		}
+	}
+
+	check("actor heap", usage.ActorHeap, l.ActorHeap)
+	check("object space", usage.Objects, l.ObjectSpace)
+
+	return warnings
+}
+
+func sortedIDs(set map[uint16]bool) []uint16 {
+	ids := make([]uint16, 0, len(set))
+	for id := range set {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	return ids
+}
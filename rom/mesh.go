@@ -0,0 +1,179 @@
+package rom
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+)
+
+// Bounds is an axis-aligned bounding box.
+type Bounds struct {
+	Min   Vec3
+	Max   Vec3
+	Empty bool `json:",omitempty"`
+}
+
+// NewBounds returns empty bounds, extending them sets them to the first point.
+func NewBounds() Bounds {
+	return Bounds{Empty: true}
+}
+
+// Extend grows the bounds to include p.
+func (b *Bounds) Extend(p Vec3) {
+	if b.Empty {
+		b.Min, b.Max, b.Empty = p, p, false
+		return
+	}
+
+	b.Min = Vec3{min16(b.Min.X, p.X), min16(b.Min.Y, p.Y), min16(b.Min.Z, p.Z)}
+	b.Max = Vec3{max16(b.Max.X, p.X), max16(b.Max.Y, p.Y), max16(b.Max.Z, p.Z)}
+}
+
+// Contains returns true if p is within the bounds, edges included.
+func (b Bounds) Contains(p Vec3) bool {
+	return !b.Empty &&
+		p.X >= b.Min.X && p.X <= b.Max.X &&
+		p.Y >= b.Min.Y && p.Y <= b.Max.Y &&
+		p.Z >= b.Min.Z && p.Z <= b.Max.Z
+}
+
+// Volume returns the volume of the bounds in cubic world units.
+func (b Bounds) Volume() float64 {
+	if b.Empty {
+		return 0
+	}
+
+	return float64(int(b.Max.X)-int(b.Min.X)) *
+		float64(int(b.Max.Y)-int(b.Min.Y)) *
+		float64(int(b.Max.Z)-int(b.Min.Z))
+}
+
+// Distance returns the euclidean distance between two points.
+func (v Vec3) Distance(o Vec3) float64 {
+	dx, dy, dz := float64(v.X)-float64(o.X), float64(v.Y)-float64(o.Y), float64(v.Z)-float64(o.Z)
+	return math.Sqrt(dx*dx + dy*dy + dz*dz)
+}
+
+func min16(a, b int16) int16 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func max16(a, b int16) int16 {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// Room mesh types (0x0A header command).
+// Sources:
+// - https://wiki.cloudmodding.com/oot/Scenes_and_Rooms#Mesh_Header
+const (
+	meshTypeSimple      = 0 // display list pairs
+	meshTypePrerendered = 1 // background images, no bounds
+	meshTypeCullable    = 2 // display list pairs with a bounding sphere
+)
+
+// F3DEX2 display list opcodes used to find the mesh vertices.
+const (
+	gVTX   = 0x01
+	gDL    = 0xDE
+	gENDDL = 0xDF
+
+	gDLNoPush       = 0x01 // G_DL parameter, branch instead of call
+	maxDisplayDepth = 8
+	maxDisplayList  = 0x4000 // commands, guards against runaway lists
+)
+
+// vertexSize is the size of a F3DEX2 vertex, it starts with its position.
+const vertexSize = 0x10
+
+// loadMeshBounds computes the room bounds from the vertices its display lists
+// load, only vertices from the room file are considered.
+func (r *Room) loadMeshBounds(rs io.ReadSeeker) {
+	r.MeshBounds = NewBounds()
+	if r.MeshSegmentOffset>>24 != roomSegment {
+		return
+	}
+
+	var header struct {
+		Type         byte
+		Count        byte
+		_            uint16
+		StartSegment uint32
+		EndSegment   uint32
+	}
+	seekSegment(rs, r.VROMStart, r.MeshSegmentOffset)
+	binary.Read(rs, binary.BigEndian, &header)
+
+	entrySize := uint32(8)
+	switch header.Type {
+	case meshTypeSimple:
+	case meshTypeCullable:
+		entrySize = 16
+	default:
+		return
+	}
+
+	lists := make([]uint32, 0, 2*int(header.Count))
+	for i := uint32(0); i < uint32(header.Count); i++ {
+		seekSegment(rs, r.VROMStart, header.StartSegment+i*entrySize+entrySize-8)
+		var opa, xlu uint32
+		binary.Read(rs, binary.BigEndian, &opa)
+		binary.Read(rs, binary.BigEndian, &xlu)
+		lists = append(lists, opa, xlu)
+	}
+
+	for _, list := range lists {
+		r.walkDisplayList(rs, list, 0)
+	}
+}
+
+func (r *Room) walkDisplayList(rs io.ReadSeeker, segOff uint32, depth int) {
+	if segOff>>24 != roomSegment || depth > maxDisplayDepth {
+		return
+	}
+
+	for i := uint32(0); i < maxDisplayList; i++ {
+		var w0, w1 uint32
+		seekSegment(rs, r.VROMStart, segOff+i*8)
+		binary.Read(rs, binary.BigEndian, &w0)
+		binary.Read(rs, binary.BigEndian, &w1)
+
+		switch w0 >> 24 {
+		case gVTX:
+			r.extendWithVertices(rs, w1, (w0>>12)&0xFF)
+		case gDL:
+			r.walkDisplayList(rs, w1, depth+1)
+			if (w0>>16)&0xFF == gDLNoPush {
+				return
+			}
+		case gENDDL:
+			return
+		}
+	}
+}
+
+func (r *Room) extendWithVertices(rs io.ReadSeeker, segOff uint32, count uint32) {
+	if segOff>>24 != roomSegment {
+		return
+	}
+
+	buf := make([]byte, count*vertexSize, count*vertexSize)
+	seekSegment(rs, r.VROMStart, segOff)
+	if _, err := io.ReadFull(rs, buf); err != nil {
+		return
+	}
+
+	for i := uint32(0); i < count; i++ {
+		vertex := buf[i*vertexSize:]
+		r.MeshBounds.Extend(Vec3{
+			X: int16(binary.BigEndian.Uint16(vertex[0:])),
+			Y: int16(binary.BigEndian.Uint16(vertex[2:])),
+			Z: int16(binary.BigEndian.Uint16(vertex[4:])),
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package rom
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// SetMessage replaces a message header and text. The message is rewritten in
+// place if it fits, or relocated to the free space at the end of
+// nes_message_data_static and the MessageTable updated otherwise, the file
+// grows if it has no space left. The caller must hold the view Lock.
+func (v *View) SetMessage(id uint16, header MessageHeader, markup string) error {
+	k, err := v.messageIndex(id)
+	if err != nil {
+		return err
+	}
+
+	// Headers coming from JSON or translation files never carry the padding.
+	header.padding = v.Messages[k].padding
+	encoded, err := EncodeMessage(header, markup)
+	if err != nil {
+		return fmt.Errorf("unable to encode message 0x%04X: %s", id, err)
+	}
+
+	msg := &v.Messages[k]
+	start := msg.VROMStart
+	if len(encoded) > msg.Size() {
+		if start, err = v.allocateMessage(len(encoded)); err != nil {
+			return err
+		}
+
+		msg.Offset = (msg.Offset & 0xFF000000) | (start - messageDataStart)
+		v.rom.MessageTable[k] = msg.MessageEntry
+
+		offset := make([]byte, 4, 4)
+		binary.BigEndian.PutUint32(offset, msg.Offset)
+		v.patch(messageTableStart+uint32(k)*8+4, offset)
+	}
+
+	v.patch(start, encoded)
+
+	msg.VROMStart = start
+	msg.MessageHeader = header
+	msg.setNames()
+	msg.setData(encoded[binary.Size(rawMessageHeader{}):])
+
+	return nil
+}
+
+// GetMessageByID returns a Message from its ID
+func (v *View) GetMessageByID(id uint16) (*Message, error) {
+	k, err := v.messageIndex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &v.Messages[k], nil
+}
+
+func (v *View) messageIndex(id uint16) (int, error) {
+	for k := range v.Messages {
+		if v.Messages[k].ID == id {
+			return k, nil
+		}
+	}
+
+	return 0, fmt.Errorf("message 0x%04X not found", id)
+}
+
+// MessageSize is the size of the new encoded data of a message, header
+// included.
+type MessageSize struct {
+	ID   uint16
+	Size int
+}
+
+// CheckMessageSpace returns an error and the ID of the first message that
+// does not fit if the given messages cannot all be set in order with
+// SetMessage because nes_message_data_static cannot grow enough to hold the
+// ones that need to be relocated.
+func (v *View) CheckMessageSpace(sizes []MessageSize) (uint16, error) {
+	end, limit, err := v.messageDataSpace()
+	if err != nil {
+		return 0, err
+	}
+
+	for _, edit := range sizes {
+		msg, err := v.GetMessageByID(edit.ID)
+		if err != nil {
+			return edit.ID, err
+		}
+
+		if edit.Size <= msg.Size() {
+			continue
+		}
+
+		end = (end+3)&^3 + uint32(edit.Size)
+		if end > limit {
+			return edit.ID, fmt.Errorf("not enough free space in message data, %d bytes over", end-limit)
+		}
+	}
+
+	return 0, nil
+}
+
+// messageDataSpace returns the end of the last message and the offset
+// nes_message_data_static can grow up to without moving.
+func (v *View) messageDataSpace() (uint32, uint32, error) {
+	file, err := v.GetFileByVROMStart(messageDataStart)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var end uint32
+	for _, msg := range v.Messages {
+		if msgEnd := msg.VROMStart + uint32(msg.Size()); msgEnd > end {
+			end = msgEnd
+		}
+	}
+
+	limit := uint32(Size)
+	for _, other := range v.Files {
+		if other.Valid && other.VROMStart >= file.VROMEnd && other.VROMStart < limit {
+			limit = other.VROMStart
+		}
+	}
+
+	return end, limit, nil
+}
+
+// allocateMessage returns the VROM offset of size free bytes after the last
+// message, nes_message_data_static is grown if needed.
+func (v *View) allocateMessage(size int) (uint32, error) {
+	file, fileIndex, err := v.fileByVROMStart(messageDataStart)
+	if err != nil {
+		return 0, err
+	}
+
+	end, _, err := v.messageDataSpace()
+	if err != nil {
+		return 0, err
+	}
+
+	start := (end + 3) &^ 3 // keep messages word-aligned
+	if start+uint32(size) <= file.VROMEnd {
+		return start, nil
+	}
+
+	// The game addresses messages from the file start, it cannot move.
+	grown := make([]byte, start+uint32(size)-file.VROMStart, start+uint32(size)-file.VROMStart)
+	copy(grown, file.data)
+	if err := v.resizeFile(fileIndex, grown, false); err != nil {
+		return 0, fmt.Errorf("not enough free space in message data, %d bytes needed: %s", size, err)
+	}
+
+	return start, nil
+}
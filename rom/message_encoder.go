@@ -0,0 +1,227 @@
+package rom
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// messageCodesByName maps markup names back to their control code.
+var messageCodesByName = func() map[string]byte {
+	names := make(map[string]byte, len(messageCodes))
+	for code, v := range messageCodes {
+		names[v.Name] = code
+	}
+	return names
+}()
+
+// messageBytesByRune maps printable characters back to their byte.
+var messageBytesByRune = func() map[rune]byte {
+	runes := make(map[rune]byte, len(messageCharacters))
+	for b, r := range messageCharacters {
+		runes[r] = b
+	}
+	return runes
+}()
+
+// ParseMessageMarkup parses the markup form returned by MessageToken.Markup
+// back into tokens.
+func ParseMessageMarkup(markup string) ([]MessageToken, error) {
+	tokens := make([]MessageToken, 0, 8)
+	var text strings.Builder
+
+	flush := func() {
+		if text.Len() > 0 {
+			tokens = append(tokens, MessageToken{Type: MessageTokenText, Text: text.String()})
+			text.Reset()
+		}
+	}
+
+	for i := 0; i < len(markup); {
+		switch {
+		case strings.HasPrefix(markup[i:], "{{"):
+			text.WriteByte('{')
+			i += 2
+		case markup[i] == '\n':
+			flush()
+			tokens = append(tokens, MessageToken{Type: MessageTokenLineBreak, Code: 0x11, Name: "\n"})
+			i++
+		case markup[i] == '{':
+			end := strings.IndexByte(markup[i:], '}')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated control code at offset %d", i)
+			}
+
+			token, err := parseMessageControlCode(markup[i+1 : i+end])
+			if err != nil {
+				return nil, fmt.Errorf("at offset %d: %s", i, err)
+			}
+
+			flush()
+			tokens = append(tokens, token)
+			i += end + 1
+		default:
+			r, size := utf8.DecodeRuneInString(markup[i:])
+			if _, err := messageByte(r); err != nil {
+				return nil, fmt.Errorf("at offset %d: %s", i, err)
+			}
+			text.WriteRune(r)
+			i += size
+		}
+	}
+	flush()
+
+	return tokens, nil
+}
+
+func parseMessageControlCode(src string) (MessageToken, error) {
+	if strings.HasPrefix(src, "0x") {
+		code, err := strconv.ParseUint(src, 0, 8)
+		if err != nil {
+			return MessageToken{}, fmt.Errorf("invalid raw control code %s", src)
+		}
+		return MessageToken{Type: MessageTokenUnknown, Code: byte(code)}, nil
+	}
+
+	name, arg := src, ""
+	if i := strings.IndexByte(src, '='); i >= 0 {
+		name, arg = src[:i], src[i+1:]
+	}
+
+	code, ok := messageCodesByName[name]
+	if !ok {
+		return MessageToken{}, fmt.Errorf("unknown control code {%s}", name)
+	}
+
+	desc := messageCodes[code]
+	token := MessageToken{Type: desc.Type, Code: code, Name: desc.Name}
+	if desc.ArgSize == 0 {
+		if arg != "" {
+			return MessageToken{}, fmt.Errorf("control code {%s} takes no argument", name)
+		}
+		return token, nil
+	}
+
+	if arg == "" {
+		return MessageToken{}, fmt.Errorf("control code {%s} requires an argument", name)
+	}
+
+	value, err := strconv.ParseUint(arg, 0, desc.ArgSize*8)
+	if err != nil {
+		return MessageToken{}, fmt.Errorf("invalid argument for {%s}: %s", name, arg)
+	}
+	token.Value = uint16(value)
+
+	return token, nil
+}
+
+// messageByte returns the byte encoding a printable character.
+func messageByte(r rune) (byte, error) {
+	if r >= 0x20 && r <= 0x7E {
+		return byte(r), nil
+	}
+
+	if b, ok := messageBytesByRune[r]; ok {
+		return b, nil
+	}
+
+	return 0, fmt.Errorf("character %q cannot be encoded", r)
+}
+
+// MessageByte returns the byte encoding a printable character, if any.
+func MessageByte(r rune) (byte, bool) {
+	b, err := messageByte(r)
+	return b, err == nil
+}
+
+// EncodeMessageTokens returns the raw message data for tokens, including the
+// end marker.
+func EncodeMessageTokens(tokens []MessageToken) ([]byte, error) {
+	var buf bytes.Buffer
+	for _, token := range tokens {
+		if token.Type == MessageTokenText {
+			for _, r := range token.Text {
+				b, err := messageByte(r)
+				if err != nil {
+					return nil, err
+				}
+				buf.WriteByte(b)
+			}
+			continue
+		}
+
+		buf.WriteByte(token.Code)
+		code, ok := messageCodes[token.Code]
+		if !ok {
+			continue
+		}
+
+		switch code.ArgSize {
+		case 1:
+			buf.WriteByte(byte(token.Value))
+		case 2:
+			binary.Write(&buf, binary.BigEndian, token.Value)
+		}
+	}
+	buf.WriteByte(messageEndCode)
+
+	return buf.Bytes(), nil
+}
+
+// EncodeMessage returns the header and data of a message as stored in ROM.
+func EncodeMessage(header MessageHeader, markup string) ([]byte, error) {
+	tokens, err := ParseMessageMarkup(markup)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := EncodeMessageTokens(tokens)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := binary.Write(&buf, binary.BigEndian, header.raw()); err != nil {
+		return nil, err
+	}
+	buf.Write(data)
+
+	return buf.Bytes(), nil
+}
+
+// rawMessageHeader is MessageHeader with its padding exposed so it can be
+// read and written back unchanged.
+// binpacked, do not change struct size
+type rawMessageHeader struct {
+	TextBoxType       byte
+	TextBoxPosition   byte
+	Icon              byte
+	NextMessageNumber uint16
+	RupeeCost         uint16
+	Padding           uint32
+}
+
+func (h MessageHeader) raw() rawMessageHeader {
+	return rawMessageHeader{
+		TextBoxType:       h.TextBoxType,
+		TextBoxPosition:   h.TextBoxPosition,
+		Icon:              h.Icon,
+		NextMessageNumber: h.NextMessageNumber,
+		RupeeCost:         h.RupeeCost,
+		Padding:           h.padding,
+	}
+}
+
+func (h rawMessageHeader) header() MessageHeader {
+	return MessageHeader{
+		TextBoxType:       h.TextBoxType,
+		TextBoxPosition:   h.TextBoxPosition,
+		Icon:              h.Icon,
+		NextMessageNumber: h.NextMessageNumber,
+		RupeeCost:         h.RupeeCost,
+		padding:           h.Padding,
+	}
+}
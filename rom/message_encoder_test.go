@@ -0,0 +1,98 @@
+package rom
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestMessageRoundTrip(t *testing.T) {
+	cases := []struct {
+		name   string
+		data   []byte
+		markup string
+	}{
+		{"text", []byte("Hello!\xBF"), "Hello!"},
+		{"line break", []byte("a\x11b\xBF"), "a\nb"},
+		{"color", []byte("\x01red\x00\xBF"), "{color:red}red{color:default}"},
+		{"byte argument", []byte("\x0A\x02fast\xBF"), "{speed=2}fast"},
+		{"word argument", []byte("\x1F\x00\x20wait\xBF"), "{delay=32}wait"},
+		{"sfx", []byte("\x1E\x69\x0C\xBF"), "{sfx=0x690C}"},
+		{"choice", []byte("Yes\x11No\xC2\xBF"), "Yes\nNo{choice2}"},
+		{"unknown code", []byte("\xE0\xBF"), "{0xE0}"},
+		{"brace", []byte("{x}\xBF"), "{{x}"},
+		{"accent", []byte("caf\x96\xBF"), "café"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			markup := MessageMarkup(tokenizeMessage(c.data))
+			if markup != c.markup {
+				t.Fatalf("expected markup %q, got %q", c.markup, markup)
+			}
+
+			tokens, err := ParseMessageMarkup(markup)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			data, err := EncodeMessageTokens(tokens)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(data, c.data) {
+				t.Fatalf("expected data % X, got % X", c.data, data)
+			}
+		})
+	}
+}
+
+func TestParseMessageMarkupErrors(t *testing.T) {
+	cases := []struct {
+		name   string
+		markup string
+	}{
+		{"unterminated", "{color:red"},
+		{"unknown code", "{blink}"},
+		{"missing argument", "{delay}"},
+		{"extra argument", "{A=1}"},
+		{"argument overflow", "{speed=256}"},
+		{"invalid raw code", "{0x100}"},
+		{"unencodable character", "日本"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if _, err := ParseMessageMarkup(c.markup); err == nil {
+				t.Fatalf("expected an error for %q", c.markup)
+			}
+		})
+	}
+}
+
+func TestEncodeMessageKeepsHeaderPadding(t *testing.T) {
+	cases := []struct {
+		name    string
+		padding uint32
+	}{
+		{"default", 0xFFFFFFFF},
+		{"zero", 0},
+		{"other", 0x12345678},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			header := MessageHeader{TextBoxType: 0x01, Icon: 0xFE, NextMessageNumber: 0xFFFF, RupeeCost: 0xFFFF, padding: c.padding}
+			encoded, err := EncodeMessage(header, "Hi")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expected := []byte{0x01, 0x00, 0xFE, 0xFF, 0xFF, 0xFF, 0xFF}
+			expected = append(expected, byte(c.padding>>24), byte(c.padding>>16), byte(c.padding>>8), byte(c.padding))
+			expected = append(expected, 'H', 'i', messageEndCode)
+			if !bytes.Equal(encoded, expected) {
+				t.Fatalf("expected % X, got % X", expected, encoded)
+			}
+		})
+	}
+}
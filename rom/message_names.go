@@ -0,0 +1,222 @@
+package rom
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// TextBoxTypeNames maps MessageHeader.TextBoxType to a human-readable name,
+// types with no documented look are named after their value.
+// Sources:
+// - https://wiki.cloudmodding.com/mm/Text_Format#Message_Header
+var TextBoxTypeNames = map[byte]string{
+	0x00: "Black",
+	0x01: "Wooden",
+	0x02: "Blue",
+	0x03: "Ocarina",
+	0x04: "None, white text",
+	0x05: "None, black text",
+	0x06: "Undocumented 0x06",
+	0x07: "Undocumented 0x07",
+	0x08: "Undocumented 0x08",
+	0x09: "Undocumented 0x09",
+	0x0A: "Undocumented 0x0A",
+	0x0B: "Undocumented 0x0B",
+	0x0C: "Undocumented 0x0C",
+	0x0D: "Bombers' Notebook",
+	0x0E: "Undocumented 0x0E",
+	0x0F: "Undocumented 0x0F",
+}
+
+// TextBoxPositionNames maps MessageHeader.TextBoxPosition to a human-readable
+// name.
+var TextBoxPositionNames = map[byte]string{
+	0x00: "Dynamic",
+	0x01: "Top",
+	0x02: "Middle",
+	0x03: "Bottom",
+}
+
+// IconNames maps MessageHeader.Icon to the name of the item it displays.
+var IconNames = map[byte]string{
+	0x00: "Ocarina of Time",
+	0x01: "Hero's Bow",
+	0x02: "Fire Arrow",
+	0x03: "Ice Arrow",
+	0x04: "Light Arrow",
+	0x05: "Fairy Ocarina",
+	0x06: "Bomb",
+	0x07: "Bombchu",
+	0x08: "Deku Stick",
+	0x09: "Deku Nut",
+	0x0A: "Magic Bean",
+	0x0B: "Slingshot",
+	0x0C: "Powder Keg",
+	0x0D: "Pictograph Box",
+	0x0E: "Lens of Truth",
+	0x0F: "Hookshot",
+	0x10: "Great Fairy's Sword",
+	0x11: "Longshot",
+	0x12: "Empty Bottle",
+	0x13: "Red Potion",
+	0x14: "Green Potion",
+	0x15: "Blue Potion",
+	0x16: "Fairy",
+	0x17: "Deku Princess",
+	0x18: "Milk",
+	0x19: "Half Milk",
+	0x1A: "Fish",
+	0x1B: "Bugs",
+	0x1C: "Blue Fire",
+	0x1D: "Poe",
+	0x1E: "Big Poe",
+	0x1F: "Spring Water",
+	0x20: "Hot Spring Water",
+	0x21: "Zora Egg",
+	0x22: "Gold Dust",
+	0x23: "Magical Mushroom",
+	0x24: "Sea Horse",
+	0x25: "Chateau Romani",
+	0x26: "Hylian Loach",
+	0x28: "Moon's Tear",
+	0x29: "Land Title Deed",
+	0x2A: "Swamp Title Deed",
+	0x2B: "Mountain Title Deed",
+	0x2C: "Ocean Title Deed",
+	0x2D: "Room Key",
+	0x2E: "Express Mail to Mama",
+	0x2F: "Letter to Kafei",
+	0x30: "Pendant of Memories",
+	0x32: "Deku Mask",
+	0x33: "Goron Mask",
+	0x34: "Zora Mask",
+	0x35: "Fierce Deity's Mask",
+	0x36: "Mask of Truth",
+	0x37: "Kafei's Mask",
+	0x38: "All-Night Mask",
+	0x39: "Bunny Hood",
+	0x3A: "Keaton Mask",
+	0x3B: "Garo's Mask",
+	0x3C: "Romani's Mask",
+	0x3D: "Circus Leader's Mask",
+	0x3E: "Postman's Hat",
+	0x3F: "Couple's Mask",
+	0x40: "Great Fairy's Mask",
+	0x41: "Gibdo Mask",
+	0x42: "Don Gero's Mask",
+	0x43: "Kamaro's Mask",
+	0x44: "Captain's Hat",
+	0x45: "Stone Mask",
+	0x46: "Bremen Mask",
+	0x47: "Blast Mask",
+	0x48: "Mask of Scents",
+	0x49: "Giant's Mask",
+	0x4D: "Kokiri Sword",
+	0x4E: "Razor Sword",
+	0x4F: "Gilded Sword",
+	0x51: "Hero's Shield",
+	0x52: "Mirror Shield",
+	0xFE: "None",
+}
+
+// MessageRange is a range of message IDs sharing the same purpose.
+type MessageRange struct {
+	Start    uint16
+	End      uint16 // inclusive
+	Category string
+}
+
+// MessageRanges categorizes message IDs, it is loaded from
+// data/message_ranges.json. Ranges are best-effort community knowledge,
+// messages outside of them are uncategorized.
+var MessageRanges = []MessageRange{}
+
+// rawMessageRange is a MessageRange as written in message_ranges.json, with
+// hexadecimal IDs.
+type rawMessageRange struct {
+	Start    string
+	End      string
+	Category string
+}
+
+// loadMessageRanges replaces MessageRanges with the ones in the given JSON.
+func loadMessageRanges(data []byte) error {
+	list := make([]rawMessageRange, 0, 32)
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+
+	ranges := make([]MessageRange, 0, len(list))
+	for _, raw := range list {
+		start, err := strconv.ParseUint(raw.Start, 0, 16)
+		if err != nil {
+			return fmt.Errorf("range %s: invalid start %q", raw.Category, raw.Start)
+		}
+		end, err := strconv.ParseUint(raw.End, 0, 16)
+		if err != nil || end < start {
+			return fmt.Errorf("range %s: invalid end %q", raw.Category, raw.End)
+		}
+
+		ranges = append(ranges, MessageRange{
+			Start:    uint16(start),
+			End:      uint16(end),
+			Category: raw.Category,
+		})
+	}
+
+	MessageRanges = ranges
+	return nil
+}
+
+// unknownName is used when a value has no known name.
+func unknownName(v byte) string {
+	return fmt.Sprintf("Unknown 0x%02X", v)
+}
+
+// TextBoxTypeName returns the name of a text box type.
+func TextBoxTypeName(v byte) string {
+	if name, ok := TextBoxTypeNames[v]; ok {
+		return name
+	}
+	return unknownName(v)
+}
+
+// TextBoxPositionName returns the name of a text box position.
+func TextBoxPositionName(v byte) string {
+	if name, ok := TextBoxPositionNames[v]; ok {
+		return name
+	}
+	return unknownName(v)
+}
+
+// IconName returns the name of a message icon.
+func IconName(v byte) string {
+	if name, ok := IconNames[v]; ok {
+		return name
+	}
+	return unknownName(v)
+}
+
+// MessageCategory returns the category of a message ID, empty if unknown.
+func MessageCategory(id uint16) string {
+	for _, r := range MessageRanges {
+		if id >= r.Start && id <= r.End {
+			return r.Category
+		}
+	}
+
+	return ""
+}
+
+// LookupName returns the value a name is mapped to in one of the name maps,
+// eg. LookupName(IconNames, "Hero's Bow").
+func LookupName(names map[byte]string, name string) (byte, bool) {
+	for v, n := range names {
+		if n == name {
+			return v, true
+		}
+	}
+
+	return 0, false
+}
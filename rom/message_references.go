@@ -0,0 +1,118 @@
+package rom
+
+import "fmt"
+
+// MessageReferenceType is the kind of data referencing a message.
+type MessageReferenceType string
+
+// Message reference types
+const (
+	MessageReferenceTitleCard MessageReferenceType = "title-card" // Scene.EntranceMessageID
+	MessageReferenceActor     MessageReferenceType = "actor"      // text ID encoded in actor params
+	MessageReferenceCutscene  MessageReferenceType = "cutscene"   // cutscene text command
+)
+
+// MessageReference locates something in the ROM that displays a message.
+type MessageReference struct {
+	Type        MessageReferenceType
+	Description string
+
+	SceneVROMStart uint32 `json:",omitempty"`
+	SceneName      string `json:",omitempty"`
+	RoomVROMStart  uint32 `json:",omitempty"`
+	ActorIndex     int    `json:",omitempty"` // in Room.ActorList
+	ActorID        uint16 `json:",omitempty"`
+	CutsceneIndex  int    `json:",omitempty"` // in Scene.Cutscenes
+}
+
+// actorMessageIDs maps actor IDs to a function returning the message IDs an
+// instance displays given its Initialization params.
+var actorMessageIDs = map[uint16]func(params uint16) []uint16{
+	0x00A8: func(params uint16) []uint16 { return []uint16{params&0x00FF | 0x0300} }, // En_Kanban
+	0x00EF: func(params uint16) []uint16 { return []uint16{params&0x001F | 0x20D0} }, // En_Gs
+	0x0223: func(params uint16) []uint16 { return []uint16{0x0C00, 0x0C01} },         // Obj_Warpstone
+}
+
+// displays returns true if the actor displays the given message.
+func displays(actor ActorEntry, id uint16) bool {
+	decode, ok := actorMessageIDs[actor.ID]
+	return ok && containsMessageID(decode(actor.Initialization), id)
+}
+
+// MessageReferences returns everything referencing a message.
+func (v *View) MessageReferences(id uint16) ([]MessageReference, error) {
+	if _, err := v.GetMessageByID(id); err != nil {
+		return nil, err
+	}
+
+	refs := make([]MessageReference, 0)
+	for _, scene := range v.Scenes {
+		if !scene.Valid {
+			continue
+		}
+
+		if scene.EntranceMessageID == id {
+			refs = append(refs, MessageReference{
+				Type:           MessageReferenceTitleCard,
+				Description:    "Scene title card",
+				SceneVROMStart: scene.VROMStart,
+				SceneName:      scene.Name,
+			})
+		}
+
+		for k, cutscene := range scene.Cutscenes {
+			for _, text := range cutscene.Texts {
+				if !containsMessageID(text.MessageIDs(), id) {
+					continue
+				}
+
+				refs = append(refs, MessageReference{
+					Type:           MessageReferenceCutscene,
+					Description:    fmt.Sprintf("Cutscene %d, frames %d-%d", k, text.StartFrame, text.EndFrame),
+					SceneVROMStart: scene.VROMStart,
+					SceneName:      scene.Name,
+					CutsceneIndex:  k,
+				})
+			}
+		}
+
+		for _, room := range scene.Rooms {
+			for k, actor := range room.ActorList {
+				if !displays(actor, id) {
+					continue
+				}
+
+				refs = append(refs, MessageReference{
+					Type:           MessageReferenceActor,
+					Description:    actorName(actor),
+					SceneVROMStart: scene.VROMStart,
+					SceneName:      scene.Name,
+					RoomVROMStart:  room.VROMStart,
+					ActorIndex:     k,
+					ActorID:        actor.ID,
+				})
+			}
+		}
+	}
+
+	return refs, nil
+}
+
+// actorName returns the most human-readable name of an actor.
+func actorName(actor ActorEntry) string {
+	if actor.Description.Identification != "" {
+		return actor.Description.Identification
+	}
+
+	return actor.Description.FileName
+}
+
+func containsMessageID(ids []uint16, id uint16) bool {
+	for _, other := range ids {
+		if other == id {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,216 @@
+package rom
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MessageTokenType is the kind of a MessageToken.
+type MessageTokenType string
+
+// Message token types
+const (
+	MessageTokenText      MessageTokenType = "text"
+	MessageTokenColor     MessageTokenType = "color"
+	MessageTokenButton    MessageTokenType = "button"
+	MessageTokenLineBreak MessageTokenType = "linebreak"
+	MessageTokenBoxBreak  MessageTokenType = "boxbreak"
+	MessageTokenDelay     MessageTokenType = "delay"
+	MessageTokenChoice    MessageTokenType = "choice"
+	MessageTokenVariable  MessageTokenType = "variable"
+	MessageTokenSFX       MessageTokenType = "sfx"
+	MessageTokenControl   MessageTokenType = "control" // anything else affecting the textbox
+	MessageTokenUnknown   MessageTokenType = "unknown"
+)
+
+// MessageToken is a single element of a message, either a run of text or a
+// control code and its argument.
+type MessageToken struct {
+	Type  MessageTokenType
+	Code  byte   // control code, unset for text
+	Name  string `json:",omitempty"` // control code name, used in markup
+	Text  string `json:",omitempty"` // text runs only
+	Value uint16 `json:",omitempty"` // control code argument
+}
+
+// messageCode describes a message control code.
+type messageCode struct {
+	Name    string
+	Type    MessageTokenType
+	ArgSize int // in bytes
+}
+
+// messageEndCode marks the end of a message
+const messageEndCode = 0xBF
+
+// messageCodes maps control codes to their description, names are used as-is
+// in the markup.
+// Sources:
+// - https://wiki.cloudmodding.com/mm/Text_Format#Control_Codes
+var messageCodes = map[byte]messageCode{
+	0x00: {"color:default", MessageTokenColor, 0},
+	0x01: {"color:red", MessageTokenColor, 0},
+	0x02: {"color:green", MessageTokenColor, 0},
+	0x03: {"color:blue", MessageTokenColor, 0},
+	0x04: {"color:yellow", MessageTokenColor, 0},
+	0x05: {"color:lightblue", MessageTokenColor, 0},
+	0x06: {"color:pink", MessageTokenColor, 0},
+	0x07: {"color:silver", MessageTokenColor, 0},
+	0x08: {"color:orange", MessageTokenColor, 0},
+	0x0A: {"speed", MessageTokenControl, 1},
+	0x0B: {"highscore", MessageTokenVariable, 0},
+	0x10: {"box", MessageTokenBoxBreak, 0},
+	0x11: {"\n", MessageTokenLineBreak, 0},
+	0x12: {"box2", MessageTokenBoxBreak, 0},
+	0x13: {"reset", MessageTokenControl, 0},
+	0x14: {"shift", MessageTokenControl, 1},
+	0x15: {"noskip", MessageTokenControl, 0},
+	0x16: {"name", MessageTokenVariable, 0},
+	0x17: {"instant", MessageTokenControl, 0},
+	0x18: {"/instant", MessageTokenControl, 0},
+	0x19: {"event", MessageTokenControl, 0},
+	0x1A: {"persistent", MessageTokenControl, 0},
+	0x1B: {"box-delay", MessageTokenBoxBreak, 2},
+	0x1C: {"fade", MessageTokenDelay, 2},
+	0x1D: {"fade-skippable", MessageTokenDelay, 2},
+	0x1E: {"sfx", MessageTokenSFX, 2},
+	0x1F: {"delay", MessageTokenDelay, 2},
+	0xB0: {"A", MessageTokenButton, 0},
+	0xB1: {"B", MessageTokenButton, 0},
+	0xB2: {"C", MessageTokenButton, 0},
+	0xB3: {"L", MessageTokenButton, 0},
+	0xB4: {"R", MessageTokenButton, 0},
+	0xB5: {"Z", MessageTokenButton, 0},
+	0xB6: {"C-Up", MessageTokenButton, 0},
+	0xB7: {"C-Down", MessageTokenButton, 0},
+	0xB8: {"C-Left", MessageTokenButton, 0},
+	0xB9: {"C-Right", MessageTokenButton, 0},
+	0xBA: {"triangle", MessageTokenButton, 0},
+	0xBB: {"stick", MessageTokenButton, 0},
+	0xBC: {"dpad", MessageTokenButton, 0},
+	0xC1: {"background", MessageTokenControl, 0},
+	0xC2: {"choice2", MessageTokenChoice, 0},
+	0xC3: {"choice3", MessageTokenChoice, 0},
+	0xC4: {"timer:postman", MessageTokenVariable, 0},
+	0xC5: {"timer:minigame1", MessageTokenVariable, 0},
+	0xC6: {"timer:2", MessageTokenVariable, 0},
+	0xC7: {"timer:moon-crash", MessageTokenVariable, 0},
+	0xC8: {"timer:minigame2", MessageTokenVariable, 0},
+	0xC9: {"timer:hazard", MessageTokenVariable, 0},
+	0xCA: {"time", MessageTokenVariable, 0},
+	0xCB: {"chest-flags", MessageTokenVariable, 0},
+	0xCC: {"input:bank", MessageTokenVariable, 0},
+	0xCD: {"rupees:selected", MessageTokenVariable, 0},
+	0xCE: {"rupees:total", MessageTokenVariable, 0},
+	0xCF: {"time:moon-crash", MessageTokenVariable, 0},
+	0xD0: {"input:doggy-bet", MessageTokenVariable, 0},
+	0xD1: {"input:bomber-code", MessageTokenVariable, 0},
+	0xD2: {"pause", MessageTokenControl, 0},
+	0xD3: {"time:speed", MessageTokenVariable, 0},
+	0xD4: {"owl-warp", MessageTokenVariable, 0},
+	0xD5: {"input:lottery", MessageTokenVariable, 0},
+	0xD6: {"spider-house-code", MessageTokenVariable, 0},
+	0xD7: {"fairies:woodfall", MessageTokenVariable, 0},
+	0xD8: {"fairies:snowhead", MessageTokenVariable, 0},
+	0xD9: {"fairies:great-bay", MessageTokenVariable, 0},
+	0xDA: {"fairies:stone-tower", MessageTokenVariable, 0},
+	0xDB: {"points:tens", MessageTokenVariable, 0},
+	0xDC: {"points:thousands", MessageTokenVariable, 0},
+}
+
+// messageCharacters maps non-ASCII character codes to their rune.
+var messageCharacters = map[byte]rune{
+	0x7F: '‾',
+	0x80: 'À', 0x81: 'î', 0x82: 'Â', 0x83: 'Ä', 0x84: 'Ç', 0x85: 'È', 0x86: 'É', 0x87: 'Ê',
+	0x88: 'Ë', 0x89: 'Ï', 0x8A: 'Ô', 0x8B: 'Ö', 0x8C: 'Ù', 0x8D: 'Û', 0x8E: 'Ü', 0x8F: 'ß',
+	0x90: 'à', 0x91: 'á', 0x92: 'â', 0x93: 'ä', 0x94: 'ç', 0x95: 'è', 0x96: 'é', 0x97: 'ê',
+	0x98: 'ë', 0x99: 'ï', 0x9A: 'ô', 0x9B: 'ö', 0x9C: 'ù', 0x9D: 'û', 0x9E: 'ü',
+}
+
+// tokenizeMessage splits raw message data (without its header) into tokens.
+// Parsing stops at the end marker, which is not part of the output.
+func tokenizeMessage(src []byte) []MessageToken {
+	tokens := make([]MessageToken, 0, 8)
+	var text strings.Builder
+
+	flush := func() {
+		if text.Len() > 0 {
+			tokens = append(tokens, MessageToken{Type: MessageTokenText, Text: text.String()})
+			text.Reset()
+		}
+	}
+
+	for i := 0; i < len(src); i++ {
+		b := src[i]
+		if b == messageEndCode {
+			break
+		}
+
+		if r, ok := messageRune(b); ok {
+			text.WriteRune(r)
+			continue
+		}
+
+		flush()
+		code, ok := messageCodes[b]
+		if !ok {
+			tokens = append(tokens, MessageToken{Type: MessageTokenUnknown, Code: b})
+			continue
+		}
+
+		token := MessageToken{Type: code.Type, Code: b, Name: code.Name}
+		for j := 0; j < code.ArgSize && i+1 < len(src); j++ {
+			i++
+			token.Value = token.Value<<8 | uint16(src[i])
+		}
+		tokens = append(tokens, token)
+	}
+	flush()
+
+	return tokens
+}
+
+// messageRune returns the printable character a byte encodes, if any.
+func messageRune(b byte) (rune, bool) {
+	if b >= 0x20 && b <= 0x7E {
+		return rune(b), true
+	}
+
+	r, ok := messageCharacters[b]
+	return r, ok
+}
+
+// Markup returns the token in the lossless markup form, text is written as-is
+// with "{" doubled, control codes are written between braces with their
+// argument after a "=", eg. "{color:red}", "{delay=32}" or "{0xE0}" for
+// unknown codes.
+func (t MessageToken) Markup() string {
+	switch t.Type {
+	case MessageTokenText:
+		return strings.Replace(t.Text, "{", "{{", -1)
+	case MessageTokenLineBreak:
+		return "\n"
+	case MessageTokenUnknown:
+		return fmt.Sprintf("{0x%02X}", t.Code)
+	}
+
+	code := messageCodes[t.Code]
+	switch {
+	case code.ArgSize == 0:
+		return fmt.Sprintf("{%s}", t.Name)
+	case t.Type == MessageTokenSFX:
+		return fmt.Sprintf("{%s=0x%04X}", t.Name, t.Value)
+	default:
+		return fmt.Sprintf("{%s=%d}", t.Name, t.Value)
+	}
+}
+
+// MessageMarkup returns the lossless markup form of a list of tokens.
+func MessageMarkup(tokens []MessageToken) string {
+	var buf strings.Builder
+	for _, token := range tokens {
+		buf.WriteString(token.Markup())
+	}
+
+	return buf.String()
+}
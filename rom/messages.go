@@ -24,6 +24,15 @@ type Message struct {
 	VROMStart uint32
 
 	String string
+	Markup string         // lossless form, see MessageToken.Markup
+	Tokens []MessageToken // exactly what the game renders
+
+	TextBoxTypeName     string
+	TextBoxPositionName string
+	IconName            string
+	Category            string // see MessageRanges
+
+	data []byte // raw message data, without header, including the end marker
 }
 
 // MessageHeader is the standard header of every text message
@@ -33,17 +42,36 @@ type MessageHeader struct {
 	Icon              byte
 	NextMessageNumber uint16
 	RupeeCost         uint16
-	_                 uint32 // 0xFFFFFFFF 0xFFFFFFFF
+
+	padding uint32 // written back as loaded, see rawMessageHeader
 }
 
+// noRupeeCost is the RupeeCost of messages not describing a shop item.
+const noRupeeCost = 0xFFFF
+
+// HasRupeeCost returns true if the message describes an item with a price.
+func (h MessageHeader) HasRupeeCost() bool {
+	return h.RupeeCost != 0 && h.RupeeCost != noRupeeCost
+}
+
+// messageDataStart is the VROM offset of nes_message_data_static, message
+// offsets are relative to it.
+const messageDataStart = 0x00AD1000
+
+// messageTableStart is the ROM offset of ROM.MessageTable.
+const messageTableStart = 0x00C5D0D8
+
 // Note: everything here is ugly
 func (f *Message) load(r io.ReadSeeker, entry MessageEntry) {
 	f.MessageEntry = entry
 	// Offset from start of message table
-	f.VROMStart = 0x00AD1000 + (entry.Offset & 0xFFFFFF) // ditch first 0x08 byte
+	f.VROMStart = messageDataStart + (entry.Offset & 0xFFFFFF) // ditch first 0x08 byte
 	r.Seek(int64(f.VROMStart), io.SeekStart)
 
-	binary.Read(r, binary.BigEndian, &f.MessageHeader)
+	var header rawMessageHeader
+	binary.Read(r, binary.BigEndian, &header)
+	f.MessageHeader = header.header()
+	f.setNames()
 
 	var b byte
 	buf := make([]byte, 0, 128)
@@ -52,7 +80,28 @@ func (f *Message) load(r io.ReadSeeker, entry MessageEntry) {
 		buf = append(buf, b)
 	}
 
-	f.String = sanitizeString(buf)
+	f.setData(buf)
+}
+
+// setNames sets the human-readable names of the header values.
+func (f *Message) setNames() {
+	f.TextBoxTypeName = TextBoxTypeName(f.TextBoxType)
+	f.TextBoxPositionName = TextBoxPositionName(f.TextBoxPosition)
+	f.IconName = IconName(f.Icon)
+	f.Category = MessageCategory(f.ID)
+}
+
+// setData sets the raw message data and everything derived from it.
+func (f *Message) setData(data []byte) {
+	f.data = data
+	f.String = sanitizeString(data)
+	f.Tokens = tokenizeMessage(data)
+	f.Markup = MessageMarkup(f.Tokens)
+}
+
+// Size returns the number of bytes the message uses in ROM, header included.
+func (f *Message) Size() int {
+	return binary.Size(rawMessageHeader{}) + len(f.data)
 }
 
 func sanitizeString(src []byte) string {
@@ -0,0 +1,77 @@
+package rom
+
+import (
+	"encoding/binary"
+	"io"
+)
+
+// MinimapList is pointed to by the 0x1C header command.
+// binpacked, do not change struct size
+type MinimapList struct {
+	EntriesSegmentOffset uint32
+	Scale                int32 // world units per map pixel
+}
+
+// MinimapEntry describes the minimap of a single room, there is one per room.
+// binpacked, do not change struct size
+type MinimapEntry struct {
+	MapID    uint16 // index of the texture in map_i_static, 0xFFFF if none
+	OffsetX  int16  // world X of the map texture origin
+	OffsetZ  int16  // world Z of the map texture origin
+	Unknown6 int16
+	Flags    uint16
+}
+
+// HasMap returns true if the room has a minimap texture.
+func (m MinimapEntry) HasMap() bool {
+	return m.MapID != 0xFFFF
+}
+
+// MapChest is a chest marker displayed on the dungeon map, pointed to by the
+// 0x1E header command.
+// binpacked, do not change struct size
+type MapChest struct {
+	RoomID    int16
+	ChestFlag int16
+	Position  Vec3
+}
+
+func (s *Scene) loadMinimaps(r io.ReadSeeker) {
+	s.MapChests = make([]MapChest, s.MapChestPositionsCount, s.MapChestPositionsCount)
+	if s.MapChestPositionsCount > 0 {
+		seekSegment(r, s.VROMStart, s.MapChestPositionsSegmentOffset)
+		binary.Read(r, binary.BigEndian, s.MapChests)
+	}
+
+	if s.MinimapsSegmentOffset == 0 {
+		return
+	}
+
+	seekSegment(r, s.VROMStart, s.MinimapsSegmentOffset)
+	binary.Read(r, binary.BigEndian, &s.MinimapList)
+	if s.MinimapList.EntriesSegmentOffset == 0 {
+		return
+	}
+
+	// There is one entry per room but rooms are loaded later, they will pick
+	// their own entry in Scene.loadRooms.
+	s.Minimaps = make([]MinimapEntry, s.RoomsCount, s.RoomsCount)
+	seekSegment(r, s.VROMStart, s.MinimapList.EntriesSegmentOffset)
+	binary.Read(r, binary.BigEndian, s.Minimaps)
+}
+
+// attachMinimap gives a Room its minimap entry and chest markers.
+func (r *Room) attachMinimap(s *Scene) {
+	if int(r.ID) < len(s.Minimaps) {
+		minimap := s.Minimaps[r.ID]
+		r.Minimap = &minimap
+		r.MinimapScale = s.MinimapList.Scale
+	}
+
+	r.MapChests = make([]MapChest, 0)
+	for _, chest := range s.MapChests {
+		if chest.RoomID == int16(r.ID) {
+			r.MapChests = append(r.MapChests, chest)
+		}
+	}
+}
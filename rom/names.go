@@ -1,11 +1,20 @@
 package rom
 
+// FileIndexNames maps NTSC-U 1.0 DMA table indices to a file name, for
+// files identified by their position in the filesystem rather than by offset.
+var FileIndexNames = map[int]string{
+	15: "map_i_static",   // dungeon map textures, see MinimapEntry.MapID
+	23: "message_static", // text box backgrounds
+}
+
 // FileNames maps file start offset to a file name
 var FileNames = map[uint32]string{
 	0x00000000: "makerom",
 	0x00001060: "boot",
 	0x0001A500: "dmadata",
+	0x00ACC000: "nes_font_static",
 	0x00AD1000: "nes_message_data_static",
+	0x00B3C000: "code",
 	// 0x00DC5A10: "", // TODO contains internal scene table",
 
 	// Scenes
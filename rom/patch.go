@@ -0,0 +1,62 @@
+package rom
+
+import (
+	"bytes"
+	"io"
+)
+
+// patch is a modification to apply to the ROM data.
+type patch struct {
+	Offset uint32
+	Data   []byte
+}
+
+// patch records a modification of the ROM at the given offset and applies it
+// to the files already loaded in memory.
+func (v *View) patch(offset uint32, data []byte) {
+	v.patchesMutex.Lock()
+	defer v.patchesMutex.Unlock()
+
+	v.patches = append(v.patches, patch{Offset: offset, Data: data})
+
+	end := offset + uint32(len(data))
+	for k := range v.Files {
+		file := &v.Files[k]
+		if !file.Valid || end <= file.VROMStart || offset >= file.VROMEnd {
+			continue
+		}
+
+		for i, b := range data {
+			pos := offset + uint32(i)
+			if pos >= file.VROMStart && pos < file.VROMEnd {
+				file.data[pos-file.VROMStart] = b
+			}
+		}
+	}
+}
+
+// IsPatched returns true if the ROM was modified since it was loaded.
+func (v *View) IsPatched() bool {
+	v.patchesMutex.Lock()
+	defer v.patchesMutex.Unlock()
+
+	return len(v.patches) > 0
+}
+
+// WriteROM writes the ROM with all modifications applied.
+func (v *View) WriteROM(w io.Writer) error {
+	v.patchesMutex.Lock()
+	defer v.patchesMutex.Unlock()
+
+	buf := make([]byte, Size, Size)
+	if _, err := v.fd.ReadAt(buf, 0); err != nil {
+		return err
+	}
+
+	for _, p := range v.patches {
+		copy(buf[p.Offset:], p.Data)
+	}
+
+	_, err := io.Copy(w, bytes.NewReader(buf))
+	return err
+}
@@ -0,0 +1,63 @@
+package rom
+
+import (
+	"encoding/binary"
+	"io"
+)
+
+// PathEntry is an entry of the scene path list (0x0D header command).
+// Sources:
+// - https://wiki.cloudmodding.com/mm/Scenes_and_Rooms#0x0D:_Paths
+// binpacked, do not change struct size
+type PathEntry struct {
+	PointsCount         byte
+	AdditionalPathIndex byte
+	CustomValue         int16
+	PointsSegmentOffset uint32
+}
+
+// Path is a list of points actors can follow.
+type Path struct {
+	PathEntry
+	Points []Vec3
+}
+
+// sceneSegment is the segment scenes are loaded in.
+const sceneSegment = 0x02
+
+// maxPaths bounds the path list, its length is not stored anywhere.
+const maxPaths = 256
+
+// loadPaths reads the path list, it ends where the first point list starts or
+// at the first entry that does not point to the scene file.
+func (s *Scene) loadPaths(r io.ReadSeeker) {
+	if s.PathsSegmentOffset == 0 {
+		return
+	}
+
+	listOffset := s.PathsSegmentOffset & 0x00FFFFFF
+	seekSegment(r, s.VROMStart, s.PathsSegmentOffset)
+
+	entries := make([]PathEntry, 0, 8)
+	end := uint32(0xFFFFFFFF)
+	for i := uint32(0); i < maxPaths && listOffset+i*8 < end; i++ {
+		var entry PathEntry
+		binary.Read(r, binary.BigEndian, &entry)
+		if entry.PointsCount == 0 || entry.PointsSegmentOffset>>24 != sceneSegment {
+			break
+		}
+
+		if offset := entry.PointsSegmentOffset & 0x00FFFFFF; offset < end {
+			end = offset
+		}
+		entries = append(entries, entry)
+	}
+
+	s.Paths = make([]Path, len(entries), len(entries))
+	for k, entry := range entries {
+		s.Paths[k].PathEntry = entry
+		s.Paths[k].Points = make([]Vec3, entry.PointsCount, entry.PointsCount)
+		seekSegment(r, s.VROMStart, entry.PointsSegmentOffset)
+		binary.Read(r, binary.BigEndian, s.Paths[k].Points)
+	}
+}
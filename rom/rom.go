@@ -16,6 +16,9 @@ var bigEndianROMHeader = [4]byte{0x80, 0x37, 0x12, 0x40}
 const mmCRC1 = 0xDA6983E7
 const mmCRC2 = 0x50674458
 
+// countryCodeJP is the CountryCode region byte of JP builds.
+const countryCodeJP = 'J'
+
 // Size is the total byte size of a ROM
 const Size = 64 * 1024 * 1024
 
@@ -45,7 +48,15 @@ type ROM struct {
 
 	DMAData [1552]DMAEntry // 0x0001A500 - 0x0020600
 
-	_ [0x00C5A1E0 - 0x0020600]byte
+	_ [0x00C45510 - 0x0020600]byte
+
+	ActorOverlayTable [690]ActorOverlay // 0x00C45510 - 0x00C4AB50
+
+	_ [0x00C58C80 - 0x00C4AB50]byte
+
+	ObjectTable [643]ObjectEntry // 0x00C58C80 - 0x00C5A098
+
+	_ [0x00C5A1E0 - 0x00C5A098]byte
 
 	InternalSceneTable [113]InternalSceneTableEntry // 0x00C5A1E0 - 0x00C5A8F0
 
@@ -53,7 +64,11 @@ type ROM struct {
 
 	MessageTable [4589]MessageEntry // 0x00C5D0D8 - 0x00C66040
 
-	_ [Size - 0x00C66040]byte
+	_ [0x00C669B0 - 0x00C66040]byte
+
+	FontWidths [160]float32 // 0x00C669B0 - 0x00C66C30, from ' '
+
+	_ [Size - 0x00C66C30]byte
 }
 
 // New loads a new ROM from a file path
@@ -90,6 +105,10 @@ func (r *ROM) validate() error {
 		)
 	}
 
+	if byte(r.CountryCode>>8) == countryCodeJP {
+		return fmt.Errorf("JP builds are not supported, their message encoding and table layout are not decoded")
+	}
+
 	if r.CRC1 != mmCRC1 {
 		return fmt.Errorf("CRC1 does not match, expected %04X got 0x%04X", mmCRC1, r.CRC1)
 	}
@@ -15,9 +15,18 @@ type Room struct {
 	SceneName      string
 	SceneVROMStart uint32 // VROM offset the the Scene this Room belongs to
 
-	ActorList []ActorEntry
+	ActorList       []ActorEntry
+	ObjectList      []uint16 // object IDs loaded with the room (0x0B header command)
+	Lights          []Light  // lights specific to the room (0x0C header command)
+	MeshBounds      Bounds   // bounds of the room geometry, empty for prerendered rooms
+	AlternateSetups []RoomSetup
 
-	data []byte
+	Minimap      *MinimapEntry // nil if the scene has no minimaps
+	MinimapScale int32
+	MapChests    []MapChest
+
+	data          []byte
+	actorCapacity int // number of actors the list has room for
 }
 
 func (r *Room) load(rs io.ReadSeeker) {
@@ -26,21 +35,40 @@ func (r *Room) load(rs io.ReadSeeker) {
 	}
 
 	r.DataStartOffset = r.LocationHeader.load(rs, r.VROMStart)
-	r.loadActors(rs)
+	r.ActorList = loadActorList(rs, r.VROMStart, r.ActorsSegmentOffset, r.ActorsCount)
+	r.actorCapacity = len(r.ActorList)
+	r.ObjectList = loadObjectList(rs, r.VROMStart, r.ObjectsSegmentOffset, r.ObjectsCount)
+	r.Lights = loadLights(rs, r.VROMStart, r.LightSettingsSegmentOffset, r.LightSettingsCount)
+	r.loadAlternateSetups(rs)
+	r.loadMeshBounds(rs)
 }
 
-func (r *Room) loadActors(rs io.ReadSeeker) {
-	r.ActorList = make([]ActorEntry, r.ActorsCount, r.ActorsCount)
-	if r.ActorsCount <= 0 {
-		return
+func loadActorList(rs io.ReadSeeker, base, segOff uint32, count byte) []ActorEntry {
+	list := make([]ActorEntry, count, count)
+	if count <= 0 {
+		return list
+	}
+
+	listOffset := segOff & 0x00FFFFFF // ditch 0x03
+	rs.Seek(int64(base+listOffset), io.SeekStart)
+
+	for i := byte(0); i < count; i++ {
+		list[i].load(rs)
 	}
 
-	listOffset := r.ActorsSegmentOffset & 0x00FFFFFF // ditch 0x03
-	rs.Seek(int64(r.VROMStart+listOffset), io.SeekStart)
+	return list
+}
 
-	for i := byte(0); i < r.ActorsCount; i++ {
-		r.ActorList[i].load(rs)
+func loadObjectList(rs io.ReadSeeker, base, segOff uint32, count byte) []uint16 {
+	list := make([]uint16, count, count)
+	if count <= 0 {
+		return list
 	}
+
+	seekSegment(rs, base, segOff)
+	binary.Read(rs, binary.BigEndian, list)
+
+	return list
 }
 
 func (r *Room) loadData(rs io.ReadSeeker, end uint32) {
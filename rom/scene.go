@@ -27,7 +27,22 @@ type Scene struct {
 	InternalSceneTableEntry
 	LocationHeader
 
-	Rooms []Room
+	Rooms     []Room
+	Collision Collision
+	Cameras   []Camera // actor cameras (0x02 header command)
+
+	EnvironmentSettings []EnvironmentSetting
+	Lights              []Light
+
+	MinimapList MinimapList
+	Minimaps    []MinimapEntry // one per room
+	MapChests   []MapChest
+
+	TextureAnimations []TextureAnimation
+	TransitionActors  []TransitionActor
+	Cutscenes         []Cutscene
+	StartPositions    []ActorEntry // player spawn points, one per entrance
+	Paths             []Path
 
 	Name            string
 	EntranceMessage string
@@ -53,6 +68,16 @@ func (s *Scene) load(r io.ReadSeeker, entry InternalSceneTableEntry) {
 	size := entry.VROMEnd - s.DataStartOffset
 	s.data = make([]byte, size, size)
 	binary.Read(r, binary.BigEndian, s.data)
+
+	s.Collision.load(r, s.VROMStart, s.CollisionHeaderSegmentOffset)
+	s.Cameras = loadCameras(r, s.VROMStart, s.CamerasSegmentOffset, int(s.CamerasCount))
+	s.loadEnvironmentSettings(r)
+	s.loadMinimaps(r)
+	s.loadTextureAnimations(r)
+	s.loadTransitionActors(r)
+	s.StartPositions = loadActorList(r, s.VROMStart, s.StartPositionsSegmentOffset, s.StartPositionsCount)
+	s.loadPaths(r)
+	s.loadCutscenes(r)
 }
 
 func (s *Scene) loadRooms(r io.ReadSeeker) {
@@ -79,5 +104,6 @@ func (s *Scene) loadRooms(r io.ReadSeeker) {
 	// Load them after because we need to keep our reader seek position.
 	for k := range s.Rooms {
 		s.Rooms[k].load(r)
+		s.Rooms[k].attachMinimap(s)
 	}
 }
@@ -0,0 +1,67 @@
+package rom
+
+import (
+	"encoding/binary"
+	"io"
+)
+
+// RoomSetup is an alternate header of a room, the game picks one depending on
+// the scene setup (story progress, cutscenes) instead of the main header.
+type RoomSetup struct {
+	Index               int    // 1-based, the main header is setup 0
+	HeaderSegmentOffset uint32 // 0 if the setup uses the main header
+
+	LocationHeader `json:"-"`
+	ActorList      []ActorEntry
+	ObjectList     []uint16
+	Lights         []Light
+}
+
+// roomSegment is the segment rooms are loaded in.
+const roomSegment = 0x03
+
+// maxAlternateSetups bounds the alternate header list, its length is not
+// stored anywhere.
+const maxAlternateSetups = 32
+
+// loadAlternateSetups reads the alternate header list (0x18 header command),
+// it ends where the first header it points to starts, or at the first value
+// that is not a pointer to the room file.
+func (r *Room) loadAlternateSetups(rs io.ReadSeeker) {
+	if r.AlternateHeadersSegmentOffset == 0 {
+		return
+	}
+
+	listOffset := r.AlternateHeadersSegmentOffset & 0x00FFFFFF
+	rs.Seek(int64(r.VROMStart+listOffset), io.SeekStart)
+
+	offsets := make([]uint32, 0, 4)
+	end := uint32(0xFFFFFFFF)
+	for i := uint32(0); i < maxAlternateSetups && listOffset+i*4 < end; i++ {
+		var offset uint32
+		binary.Read(rs, binary.BigEndian, &offset)
+		if offset != 0 && offset>>24 != roomSegment {
+			break
+		}
+
+		if offset != 0 && offset&0x00FFFFFF < end {
+			end = offset & 0x00FFFFFF
+		}
+		offsets = append(offsets, offset)
+	}
+
+	r.AlternateSetups = make([]RoomSetup, len(offsets), len(offsets))
+	for k, offset := range offsets {
+		setup := &r.AlternateSetups[k]
+		setup.Index = k + 1
+		setup.HeaderSegmentOffset = offset
+		if offset == 0 {
+			continue
+		}
+
+		setup.LocationHeader.load(rs, r.VROMStart+(offset&0x00FFFFFF))
+		setup.ActorList = loadActorList(rs, r.VROMStart, setup.ActorsSegmentOffset, setup.ActorsCount)
+		setup.ObjectList = loadObjectList(rs, r.VROMStart, setup.ObjectsSegmentOffset, setup.ObjectsCount)
+		setup.Lights = loadLights(rs, r.VROMStart, setup.LightSettingsSegmentOffset, setup.LightSettingsCount)
+	}
+}
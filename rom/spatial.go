@@ -0,0 +1,98 @@
+package rom
+
+import "sort"
+
+// ActorHit is an actor found by a spatial query.
+type ActorHit struct {
+	ActorOccurrence
+	ActorID  uint16
+	Name     string
+	Distance float64 // from the query point
+}
+
+// newActorHit returns a hit for an actor at its distance from p.
+func newActorHit(occurrence ActorOccurrence, actor ActorEntry, p Vec3) ActorHit {
+	name := actor.Description.Identification
+	if name == "" {
+		name = actor.Description.FileName
+	}
+
+	return ActorHit{
+		ActorOccurrence: occurrence,
+		ActorID:         actor.ID,
+		Name:            name,
+		Distance:        actor.Position.Distance(p),
+	}
+}
+
+// ActorsWithinRadius returns the actors of the scene at most radius world
+// units away from p, nearest first. A negative setup includes all setups.
+func (s *Scene) ActorsWithinRadius(p Vec3, radius float64, setup int) []ActorHit {
+	hits := make([]ActorHit, 0, 16)
+	s.eachActor(func(occurrence ActorOccurrence, actor ActorEntry) {
+		if setup >= 0 && occurrence.Setup != setup {
+			return
+		}
+
+		if hit := newActorHit(occurrence, actor, p); hit.Distance <= radius {
+			hits = append(hits, hit)
+		}
+	})
+
+	sort.SliceStable(hits, func(i, j int) bool {
+		return hits[i].Distance < hits[j].Distance
+	})
+
+	return hits
+}
+
+// NearestActor returns the actor of the given ID nearest to p. A negative
+// setup includes all setups.
+func (s *Scene) NearestActor(p Vec3, id uint16, setup int) (ActorHit, bool) {
+	var (
+		nearest ActorHit
+		found   bool
+	)
+
+	s.eachActor(func(occurrence ActorOccurrence, actor ActorEntry) {
+		if actor.ID != id || (setup >= 0 && occurrence.Setup != setup) {
+			return
+		}
+
+		if hit := newActorHit(occurrence, actor, p); !found || hit.Distance < nearest.Distance {
+			nearest, found = hit, true
+		}
+	})
+
+	return nearest, found
+}
+
+// ContentsBounds returns the bounds of the room geometry and of the actors of
+// its main setup.
+func (r *Room) ContentsBounds() Bounds {
+	bounds := r.MeshBounds
+	for _, actor := range r.ActorList {
+		bounds.Extend(actor.Position)
+	}
+
+	return bounds
+}
+
+// RoomAt returns the room of the scene containing p. Rooms are matched using
+// the bounds of their geometry, when many rooms overlap the smallest one is
+// picked as it is the most specific.
+func (s *Scene) RoomAt(p Vec3) (*Room, bool) {
+	var room *Room
+	for k := range s.Rooms {
+		candidate := &s.Rooms[k]
+		if !candidate.MeshBounds.Contains(p) {
+			continue
+		}
+
+		if room == nil || candidate.MeshBounds.Volume() < room.MeshBounds.Volume() {
+			room = candidate
+		}
+	}
+
+	return room, room != nil
+}
@@ -0,0 +1,81 @@
+package rom
+
+import "fmt"
+
+// HalfDay is the day or night half of a day of the three-day cycle. Day 0 is
+// the time before the first day starts and day 4 the time after the third
+// ends, both only happen during cutscenes.
+type HalfDay struct {
+	Day   int
+	Night bool
+	Name  string
+}
+
+// Days before, of, and after the three-day cycle.
+const (
+	FirstDay = 0
+	LastDay  = 4
+)
+
+// spawnTimeAll is the value of SpawnTimeFlags of actors always present, the
+// game also treats 0 as such.
+const spawnTimeAll = 0x3FF
+
+// NewHalfDay returns a HalfDay, ok is false if day is out of range.
+func NewHalfDay(day int, night bool) (HalfDay, bool) {
+	if day < FirstDay || day > LastDay {
+		return HalfDay{}, false
+	}
+
+	name := "Day"
+	if night {
+		name = "Night"
+	}
+
+	return HalfDay{Day: day, Night: night, Name: fmt.Sprintf("%s %d", name, day)}, true
+}
+
+// bit returns the SpawnTimeFlags bit of the half-day, day 0 is the highest
+// bit, night 4 the lowest.
+func (h HalfDay) bit() uint16 {
+	index := uint(h.Day * 2)
+	if h.Night {
+		index++
+	}
+
+	return 1 << (9 - index)
+}
+
+// SpawnsAt returns true if the actor is present during the half-day.
+func (a ActorEntry) SpawnsAt(h HalfDay) bool {
+	flags := a.SpawnTimeFlags
+	if flags == 0 {
+		flags = spawnTimeAll
+	}
+
+	return flags&h.bit() != 0
+}
+
+func (a *ActorEntry) loadSpawnHalfDays() {
+	a.SpawnHalfDays = make([]HalfDay, 0, 2*(LastDay+1))
+	for day := FirstDay; day <= LastDay; day++ {
+		for _, night := range []bool{false, true} {
+			h, _ := NewHalfDay(day, night)
+			if a.SpawnsAt(h) {
+				a.SpawnHalfDays = append(a.SpawnHalfDays, h)
+			}
+		}
+	}
+}
+
+// ActorsAt returns the actors of the room present during the half-day.
+func (r *Room) ActorsAt(h HalfDay) []ActorEntry {
+	actors := make([]ActorEntry, 0, len(r.ActorList))
+	for _, actor := range r.ActorList {
+		if actor.SpawnsAt(h) {
+			actors = append(actors, actor)
+		}
+	}
+
+	return actors
+}
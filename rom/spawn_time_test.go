@@ -0,0 +1,82 @@
+package rom
+
+import "testing"
+
+func TestHalfDayBit(t *testing.T) {
+	cases := []struct {
+		day      int
+		night    bool
+		expected uint16
+	}{
+		{0, false, 0x200},
+		{0, true, 0x100},
+		{1, false, 0x080},
+		{1, true, 0x040},
+		{2, false, 0x020},
+		{2, true, 0x010},
+		{3, false, 0x008},
+		{3, true, 0x004},
+		{4, false, 0x002},
+		{4, true, 0x001},
+	}
+
+	for _, c := range cases {
+		h, ok := NewHalfDay(c.day, c.night)
+		if !ok {
+			t.Fatalf("day %d is out of range", c.day)
+		}
+		if got := h.bit(); got != c.expected {
+			t.Errorf("%s: expected bit 0x%03X, got 0x%03X", h.Name, c.expected, got)
+		}
+	}
+}
+
+func TestNewHalfDayRange(t *testing.T) {
+	for _, day := range []int{FirstDay - 1, LastDay + 1} {
+		if _, ok := NewHalfDay(day, false); ok {
+			t.Errorf("day %d should be out of range", day)
+		}
+	}
+}
+
+func TestSpawnsAt(t *testing.T) {
+	cases := []struct {
+		name     string
+		flags    uint16
+		expected []string // names of the half-days the actor is present
+	}{
+		{"zero means always", 0x000, []string{
+			"Day 0", "Night 0", "Day 1", "Night 1", "Day 2", "Night 2", "Day 3", "Night 3", "Day 4", "Night 4",
+		}},
+		{"all", spawnTimeAll, []string{
+			"Day 0", "Night 0", "Day 1", "Night 1", "Day 2", "Night 2", "Day 3", "Night 3", "Day 4", "Night 4",
+		}},
+		{"first day only", 0x080, []string{"Day 1"}},
+		{"nights", 0x155, []string{"Night 0", "Night 1", "Night 2", "Night 3", "Night 4"}},
+		{"final night and after", 0x007, []string{"Night 3", "Day 4", "Night 4"}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			actor := ActorEntry{SpawnTimeFlags: c.flags}
+			actor.loadSpawnHalfDays()
+
+			got := make([]string, len(actor.SpawnHalfDays), len(actor.SpawnHalfDays))
+			for k, h := range actor.SpawnHalfDays {
+				got[k] = h.Name
+				if !actor.SpawnsAt(h) {
+					t.Errorf("SpawnsAt(%s) is false", h.Name)
+				}
+			}
+
+			if len(got) != len(c.expected) {
+				t.Fatalf("expected %v, got %v", c.expected, got)
+			}
+			for k := range got {
+				if got[k] != c.expected[k] {
+					t.Fatalf("expected %v, got %v", c.expected, got)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,360 @@
+package rom
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// TextureAnimationEntry is a single entry of the list pointed to by the 0x1A
+// header command.
+// Sources:
+// - https://wiki.cloudmodding.com/mm/Scenes_and_Rooms#0x1A_Texture_Animations
+// binpacked, do not change struct size
+type TextureAnimationEntry struct {
+	Segment             int8 // negative on the last entry
+	_                   byte
+	Type                int16
+	ParamsSegmentOffset uint32
+}
+
+// Texture animation types
+const (
+	TextureAnimationScroll         = 0
+	TextureAnimationTwoScroll      = 1
+	TextureAnimationColor          = 2
+	TextureAnimationColorLerp      = 3
+	TextureAnimationColorNonLinear = 4
+	TextureAnimationCycle          = 5
+	TextureAnimationConditional    = 6
+)
+
+const (
+	maxTextureAnimationsPerScene      = 64 // in case the end marker is missing
+	maxTextureAnimationColorKeyFrames = 256
+)
+
+// TextureAnimationTypeNames maps texture animation types to a human-readable
+// name.
+var TextureAnimationTypeNames = map[int16]string{
+	TextureAnimationScroll:         "scroll",
+	TextureAnimationTwoScroll:      "two layers scroll",
+	TextureAnimationColor:          "color",
+	TextureAnimationColorLerp:      "color (linear interpolation)",
+	TextureAnimationColorNonLinear: "color (non-linear interpolation)",
+	TextureAnimationCycle:          "texture cycle (flipbook)",
+	TextureAnimationConditional:    "conditional",
+}
+
+// TextureAnimation is an animated material, the game applies it to the display
+// lists using Segment.
+type TextureAnimation struct {
+	TextureAnimationEntry
+
+	DisplayListSegment byte
+	TypeName           string
+
+	// Only one of these is set depending on Type.
+	Scrolls     []TextureScroll     `json:",omitempty"`
+	Color       *ColorAnimation     `json:",omitempty"`
+	Cycle       *TextureCycle       `json:",omitempty"`
+	Conditional *TextureConditional `json:",omitempty"`
+}
+
+// TextureScroll is the step by which a texture layer is moved on each frame.
+// binpacked, do not change struct size
+type TextureScroll struct {
+	XStep  int8
+	YStep  int8
+	Width  byte
+	Height byte
+}
+
+// ColorAnimationParams are the parameters shared by all color animations.
+// binpacked, do not change struct size
+type ColorAnimationParams struct {
+	KeyFrameLength          uint16 // total animation length in frames
+	KeyFrameCount           uint16
+	PrimColorsSegmentOffset uint32
+	EnvColorsSegmentOffset  uint32 // 0 if the environment color is not animated
+	KeyFramesSegmentOffset  uint32 // 0 for non-interpolated animations
+}
+
+// PrimColor is a primitive color with its LOD fraction.
+// binpacked, do not change struct size
+type PrimColor struct {
+	R       byte
+	G       byte
+	B       byte
+	A       byte
+	LODFrac byte
+}
+
+// EnvColor is an environment color.
+// binpacked, do not change struct size
+type EnvColor struct {
+	R byte
+	G byte
+	B byte
+	A byte
+}
+
+// ColorAnimation animates the primitive and environment colors.
+type ColorAnimation struct {
+	ColorAnimationParams
+
+	PrimColors []PrimColor
+	EnvColors  []EnvColor
+	KeyFrames  []uint16
+}
+
+// TextureCycleParams are the parameters of a flipbook animation.
+// binpacked, do not change struct size
+type TextureCycleParams struct {
+	KeyFrameLength        uint16
+	_                     uint16
+	TexturesSegmentOffset uint32
+	IndicesSegmentOffset  uint32
+}
+
+// TextureCycle displays a texture from Textures on each frame, using Indices
+// to pick which one.
+type TextureCycle struct {
+	TextureCycleParams
+
+	Textures []uint32 // segmented addresses
+	Indices  []byte   // one per frame
+}
+
+// TextureConditional is a conditional animation. The entries found in the
+// game have no params (00 00 00 06 00000000), they draw nothing and only
+// reserve their segment. Params are not decoded since no shipped entry has
+// any, see ParamsSegmentOffset.
+type TextureConditional struct {
+	Empty bool // no params, the game draws nothing
+}
+
+func (s *Scene) loadTextureAnimations(r io.ReadSeeker) {
+	s.TextureAnimations = make([]TextureAnimation, 0)
+	if s.TextureAnimationsSegmentOffset == 0 {
+		return
+	}
+
+	seekSegment(r, s.VROMStart, s.TextureAnimationsSegmentOffset)
+	for i := 0; i < maxTextureAnimationsPerScene; i++ {
+		var anim TextureAnimation
+		binary.Read(r, binary.BigEndian, &anim.TextureAnimationEntry)
+		s.TextureAnimations = append(s.TextureAnimations, anim)
+
+		if anim.Segment < 0 {
+			break
+		}
+	}
+
+	// Load params after because we need to keep our reader seek position.
+	for k := range s.TextureAnimations {
+		s.TextureAnimations[k].load(r, s.VROMStart)
+	}
+}
+
+func (a *TextureAnimation) load(r io.ReadSeeker, base uint32) {
+	segment := int(a.Segment)
+	if segment < 0 {
+		segment = -segment
+	}
+	a.DisplayListSegment = byte(segment + 7)
+
+	var ok bool
+	if a.TypeName, ok = TextureAnimationTypeNames[a.Type]; !ok {
+		a.TypeName = fmt.Sprintf("unknown 0x%02X", a.Type)
+	}
+
+	if a.Type == TextureAnimationConditional {
+		a.Conditional = &TextureConditional{Empty: a.ParamsSegmentOffset == 0}
+	}
+
+	if a.ParamsSegmentOffset == 0 {
+		return
+	}
+
+	seekSegment(r, base, a.ParamsSegmentOffset)
+	switch a.Type {
+	case TextureAnimationScroll:
+		a.Scrolls = make([]TextureScroll, 1, 1)
+		binary.Read(r, binary.BigEndian, a.Scrolls)
+	case TextureAnimationTwoScroll:
+		a.Scrolls = make([]TextureScroll, 2, 2)
+		binary.Read(r, binary.BigEndian, a.Scrolls)
+	case TextureAnimationColor, TextureAnimationColorLerp, TextureAnimationColorNonLinear:
+		a.Color = &ColorAnimation{}
+		a.Color.load(r, base, a.Type)
+	case TextureAnimationCycle:
+		a.Cycle = &TextureCycle{}
+		a.Cycle.load(r, base)
+	}
+}
+
+func (c *ColorAnimation) load(r io.ReadSeeker, base uint32, typ int16) {
+	binary.Read(r, binary.BigEndian, &c.ColorAnimationParams)
+
+	// Non-interpolated animations have one color per frame, the others have
+	// one per key frame.
+	count := int(c.KeyFrameCount)
+	if typ == TextureAnimationColor {
+		count = int(c.KeyFrameLength)
+	}
+	if count > maxTextureAnimationColorKeyFrames {
+		count = maxTextureAnimationColorKeyFrames
+	}
+
+	if c.PrimColorsSegmentOffset != 0 {
+		c.PrimColors = make([]PrimColor, count, count)
+		seekSegment(r, base, c.PrimColorsSegmentOffset)
+		binary.Read(r, binary.BigEndian, c.PrimColors)
+	}
+
+	if c.EnvColorsSegmentOffset != 0 {
+		c.EnvColors = make([]EnvColor, count, count)
+		seekSegment(r, base, c.EnvColorsSegmentOffset)
+		binary.Read(r, binary.BigEndian, c.EnvColors)
+	}
+
+	if c.KeyFramesSegmentOffset != 0 {
+		c.KeyFrames = make([]uint16, count, count)
+		seekSegment(r, base, c.KeyFramesSegmentOffset)
+		binary.Read(r, binary.BigEndian, c.KeyFrames)
+	}
+}
+
+func (c *TextureCycle) load(r io.ReadSeeker, base uint32) {
+	binary.Read(r, binary.BigEndian, &c.TextureCycleParams)
+	if c.IndicesSegmentOffset == 0 || c.TexturesSegmentOffset == 0 {
+		return
+	}
+
+	c.Indices = make([]byte, c.KeyFrameLength, c.KeyFrameLength)
+	seekSegment(r, base, c.IndicesSegmentOffset)
+	binary.Read(r, binary.BigEndian, c.Indices)
+
+	count := 0
+	for _, index := range c.Indices {
+		if int(index) >= count {
+			count = int(index) + 1
+		}
+	}
+
+	c.Textures = make([]uint32, count, count)
+	seekSegment(r, base, c.TexturesSegmentOffset)
+	binary.Read(r, binary.BigEndian, c.Textures)
+}
+
+// Frames returns the number of frames after which the animation loops, 0 if
+// it does not have a fixed length.
+func (a *TextureAnimation) Frames() int {
+	switch {
+	case a.Color != nil:
+		return int(a.Color.KeyFrameLength)
+	case a.Cycle != nil:
+		return int(a.Cycle.KeyFrameLength)
+	}
+
+	return 0
+}
+
+// PrimColorAt returns the primitive color displayed on the given frame.
+// Non-linear interpolation is approximated linearly.
+func (c *ColorAnimation) PrimColorAt(frame int) PrimColor {
+	from, to, t, ok := c.keyFrameAt(frame, len(c.PrimColors))
+	if !ok {
+		return PrimColor{}
+	}
+
+	a, b := c.PrimColors[from], c.PrimColors[to]
+	return PrimColor{
+		R:       t.lerp(a.R, b.R),
+		G:       t.lerp(a.G, b.G),
+		B:       t.lerp(a.B, b.B),
+		A:       t.lerp(a.A, b.A),
+		LODFrac: t.lerp(a.LODFrac, b.LODFrac),
+	}
+}
+
+// EnvColorAt returns the environment color displayed on the given frame and
+// false if the environment color is not animated.
+// Non-linear interpolation is approximated linearly.
+func (c *ColorAnimation) EnvColorAt(frame int) (EnvColor, bool) {
+	from, to, t, ok := c.keyFrameAt(frame, len(c.EnvColors))
+	if !ok {
+		return EnvColor{}, false
+	}
+
+	a, b := c.EnvColors[from], c.EnvColors[to]
+	return EnvColor{
+		R: t.lerp(a.R, b.R),
+		G: t.lerp(a.G, b.G),
+		B: t.lerp(a.B, b.B),
+		A: t.lerp(a.A, b.A),
+	}, true
+}
+
+// keyFrameProgress is the position of a frame between two key frames.
+type keyFrameProgress struct {
+	elapsed, duration int
+}
+
+func (p keyFrameProgress) lerp(a, b byte) byte {
+	if p.duration <= 0 {
+		return a
+	}
+
+	return byte(int(a) + (int(b)-int(a))*p.elapsed/p.duration)
+}
+
+// keyFrameAt returns the indices of the colors to interpolate between on the
+// given frame, count being the number of colors.
+func (c *ColorAnimation) keyFrameAt(frame, count int) (int, int, keyFrameProgress, bool) {
+	if count == 0 {
+		return 0, 0, keyFrameProgress{}, false
+	}
+
+	if c.KeyFrameLength > 0 {
+		frame %= int(c.KeyFrameLength)
+	}
+
+	if len(c.KeyFrames) == 0 {
+		if frame >= count {
+			frame = count - 1
+		}
+		return frame, frame, keyFrameProgress{}, true
+	}
+
+	i := 1
+	for ; i < len(c.KeyFrames) && i < count; i++ {
+		if frame < int(c.KeyFrames[i]) {
+			break
+		}
+	}
+	if i >= len(c.KeyFrames) || i >= count {
+		return i - 1, i - 1, keyFrameProgress{}, true
+	}
+
+	return i - 1, i, keyFrameProgress{
+		elapsed:  frame - int(c.KeyFrames[i-1]),
+		duration: int(c.KeyFrames[i]) - int(c.KeyFrames[i-1]),
+	}, true
+}
+
+// TextureAt returns the segmented address of the texture displayed on the
+// given frame.
+func (c *TextureCycle) TextureAt(frame int) (uint32, bool) {
+	if len(c.Indices) == 0 {
+		return 0, false
+	}
+
+	index := int(c.Indices[frame%len(c.Indices)])
+	if index >= len(c.Textures) {
+		return 0, false
+	}
+
+	return c.Textures[index], true
+}
@@ -0,0 +1,62 @@
+package rom
+
+import (
+	"encoding/binary"
+	"io"
+)
+
+// TransitionActorEntry is an actor linking two rooms, usually a door.
+// Sources:
+// - https://wiki.cloudmodding.com/oot/Scenes_and_Rooms#Transition_Actors
+// binpacked, do not change struct size
+type TransitionActorEntry struct {
+	FrontRoom      int8
+	FrontCamera    int8
+	BackRoom       int8
+	BackCamera     int8
+	ID             uint16 // upper bits are flags
+	Position       Vec3
+	Rotation       int16 // Y rotation in degrees and cutscene index, see TransitionActor
+	Initialization uint16
+}
+
+// TransitionActor is a decoded TransitionActorEntry.
+type TransitionActor struct {
+	TransitionActorEntry
+
+	RotationDegrees int // Y rotation, bits 7-15 of Rotation
+	CutsceneIndex   int // bits 0-6 of Rotation, transitionActorNoCutscene if none
+
+	Description       ActorDescription
+	Params            []ActorParam `json:",omitempty"`
+	ParamsDescription string       `json:",omitempty"`
+}
+
+// transitionActorIDMask masks out the flags of TransitionActorEntry.ID.
+const transitionActorIDMask = 0x1FFF
+
+// transitionActorNoCutscene is the TransitionActor.CutsceneIndex of actors
+// not playing a cutscene.
+const transitionActorNoCutscene = 0x7F
+
+func (s *Scene) loadTransitionActors(r io.ReadSeeker) {
+	s.TransitionActors = make([]TransitionActor, s.ActorTransitionsCount, s.ActorTransitionsCount)
+	if s.ActorTransitionsCount <= 0 {
+		return
+	}
+
+	seekSegment(r, s.VROMStart, s.ActorTransitionsSegmentOffset)
+	for k := range s.TransitionActors {
+		actor := &s.TransitionActors[k]
+		binary.Read(r, binary.BigEndian, &actor.TransitionActorEntry)
+		actor.RotationDegrees = int(uint16(actor.Rotation)>>7) & 0x1FF
+		actor.CutsceneIndex = int(actor.Rotation) & transitionActorNoCutscene
+
+		id := actor.ID & transitionActorIDMask
+		actor.Description = ActorDescriptions[id]
+		if schema, ok := ActorParamSchemas[id]; ok {
+			actor.Params = schema.Decode(actor.Initialization)
+			actor.ParamsDescription = schema.Describe(actor.Initialization)
+		}
+	}
+}
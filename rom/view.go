@@ -6,6 +6,7 @@ import (
 	"io"
 	"log"
 	"os"
+	"sync"
 
 	"github.com/dustin/go-humanize"
 )
@@ -16,8 +17,19 @@ type View struct {
 	Scenes   []Scene
 	Messages []Message
 
+	ActorOverlays []ActorOverlay // indexed by actor ID
+	Objects       []ObjectEntry  // indexed by object ID
+
+	FontWidths FontWidths
+	FontGlyphs []byte // I4 textures, see View.Glyph
+
 	rom *ROM
 	fd  *os.File
+
+	patches      []patch
+	patchesMutex sync.Mutex
+
+	mutex sync.RWMutex // see Lock and RLock
 }
 
 // NewView creates a new view from a ROM
@@ -62,6 +74,28 @@ func NewView(path string) (*View, error) {
 	return v, nil
 }
 
+// Lock locks the view for editing. Methods modifying the view (SetMessage,
+// SaveRoomActors, …) do not lock it themselves, callers must hold this lock
+// while editing and while modifying the data they pass to them.
+func (v *View) Lock() {
+	v.mutex.Lock()
+}
+
+// Unlock unlocks the view after Lock.
+func (v *View) Unlock() {
+	v.mutex.Unlock()
+}
+
+// RLock locks the view for reading, edits wait until RUnlock is called.
+func (v *View) RLock() {
+	v.mutex.RLock()
+}
+
+// RUnlock unlocks the view after RLock.
+func (v *View) RUnlock() {
+	v.mutex.RUnlock()
+}
+
 // Close implements io.Closer
 func (v *View) Close() {
 	v.fd.Close()
@@ -72,6 +106,10 @@ func (v *View) load(r io.ReadSeeker) error {
 		return err
 	}
 
+	v.loadCodeTables()
+	v.loadFontWidths()
+	v.loadFontGlyphs()
+
 	if err := v.loadMessages(r); err != nil {
 		return err
 	}
@@ -127,6 +165,9 @@ func (v *View) loadFiles(r io.ReadSeeker) error {
 	size := 0
 	for k, entry := range v.rom.DMAData {
 		v.Files[k].load(r, entry)
+		if name, ok := FileIndexNames[k]; ok && v.Files[k].Valid && v.Files[k].Name == "" {
+			v.Files[k].Name = name
+		}
 		size += len(v.Files[k].data)
 	}
 
@@ -209,6 +250,16 @@ func (v *View) GetFileByVROMStart(start uint32) (*File, error) {
 	return nil, errors.New("file not found")
 }
 
+// GetFileByName returns a File from its name in FileNames
+func (v *View) GetFileByName(name string) (*File, error) {
+	for k := range v.Files {
+		if v.Files[k].Valid && v.Files[k].Name == name {
+			return &v.Files[k], nil
+		}
+	}
+	return nil, errors.New("file not found")
+}
+
 // GetSceneByVROMStart returns a Scene from a VROMStart
 func (v *View) GetSceneByVROMStart(start uint32) (*Scene, error) {
 	for k := range v.Scenes {
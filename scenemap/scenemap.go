@@ -0,0 +1,271 @@
+package scenemap
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"image/color"
+	"io"
+	"sort"
+
+	"github.com/L-P/mme/rom"
+)
+
+// floorNormalMin is the minimum normal Y of a floor polygon, normals are
+// stored as fixed point with 0x7FFF being 1.0, this accepts slopes up to 60°.
+const floorNormalMin = 0x7FFF / 2
+
+// margin around the map, in world units.
+const margin = 100
+
+var (
+	backgroundColor = color.NRGBA{0x20, 0x20, 0x20, 0xFF}
+	lowFloorColor   = color.NRGBA{0x20, 0x38, 0x60, 0xFF}
+	highFloorColor  = color.NRGBA{0xE8, 0xE0, 0xB0, 0xFF}
+	roomColor       = color.NRGBA{0xFF, 0xFF, 0xFF, 0xFF}
+	pathColor       = color.NRGBA{0x40, 0xD0, 0xFF, 0xFF}
+	doorColor       = color.NRGBA{0xC0, 0x70, 0x30, 0xFF}
+	spawnColor      = color.NRGBA{0x30, 0xE0, 0x30, 0xFF}
+	actorColor      = color.NRGBA{0xFF, 0x40, 0x40, 0xFF}
+	labelColor      = color.NRGBA{0xFF, 0xFF, 0xFF, 0xFF}
+)
+
+type floor struct {
+	points [3]rom.Vec3
+	height float64
+}
+
+// Generate renders a top-down SVG map of a scene: floor collision polygons
+// shaded by height, room boundaries, paths, doors, spawn points and the
+// actors of each room main setup. The X axis points right and the Z axis
+// points down.
+func Generate(w io.Writer, scene *rom.Scene) error {
+	floors := sceneFloors(scene)
+	bounds := sceneBounds(scene, floors)
+	if bounds.Empty {
+		bounds.Extend(rom.Vec3{})
+	}
+
+	width := int(bounds.Max.X) - int(bounds.Min.X) + 2*margin
+	height := int(bounds.Max.Z) - int(bounds.Min.Z) + 2*margin
+	unit := float64(width) // marker and text size
+	if height > width {
+		unit = float64(height)
+	}
+	unit /= 250
+
+	buf := bufio.NewWriter(w)
+	fmt.Fprintf(buf, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="%d %d %d %d">`+"\n",
+		int(bounds.Min.X)-margin, int(bounds.Min.Z)-margin, width, height,
+	)
+	fmt.Fprintf(buf, `<title>%s</title>`+"\n", html.EscapeString(sceneTitle(scene)))
+	fmt.Fprintf(buf, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s"/>`+"\n",
+		int(bounds.Min.X)-margin, int(bounds.Min.Z)-margin, width, height, hex(backgroundColor),
+	)
+
+	writeFloors(buf, floors)
+	writeRooms(buf, scene, unit)
+	writePaths(buf, scene, unit)
+	writeDoors(buf, scene, unit)
+	writeSpawns(buf, scene, unit)
+	writeActors(buf, scene, unit)
+
+	fmt.Fprintln(buf, `</svg>`)
+
+	return buf.Flush()
+}
+
+// sceneFloors returns the floor polygons of the scene collision, lowest first
+// so higher floors are drawn over them.
+func sceneFloors(scene *rom.Scene) []floor {
+	vertices := scene.Collision.Vertices
+	floors := make([]floor, 0, len(scene.Collision.Polygons))
+
+loop:
+	for _, polygon := range scene.Collision.Polygons {
+		if polygon.Normal.Y < floorNormalMin {
+			continue
+		}
+
+		var f floor
+		for k, index := range polygon.Vertices() {
+			if int(index) >= len(vertices) {
+				continue loop
+			}
+			f.points[k] = vertices[index]
+			f.height += float64(vertices[index].Y) / 3
+		}
+		floors = append(floors, f)
+	}
+
+	sort.SliceStable(floors, func(i, j int) bool {
+		return floors[i].height < floors[j].height
+	})
+
+	return floors
+}
+
+func sceneBounds(scene *rom.Scene, floors []floor) rom.Bounds {
+	bounds := rom.NewBounds()
+	for _, f := range floors {
+		for _, p := range f.points {
+			bounds.Extend(p)
+		}
+	}
+
+	for _, room := range scene.Rooms {
+		if !room.MeshBounds.Empty {
+			bounds.Extend(room.MeshBounds.Min)
+			bounds.Extend(room.MeshBounds.Max)
+		}
+		for _, actor := range room.ActorList {
+			bounds.Extend(actor.Position)
+		}
+	}
+
+	for _, actor := range scene.TransitionActors {
+		bounds.Extend(actor.Position)
+	}
+	for _, actor := range scene.StartPositions {
+		bounds.Extend(actor.Position)
+	}
+	for _, path := range scene.Paths {
+		for _, p := range path.Points {
+			bounds.Extend(p)
+		}
+	}
+
+	return bounds
+}
+
+func writeFloors(w io.Writer, floors []floor) {
+	if len(floors) == 0 {
+		return
+	}
+
+	low, high := floors[0].height, floors[len(floors)-1].height
+	fmt.Fprintln(w, `<g id="floors">`)
+	for _, f := range floors {
+		ratio := 0.0
+		if high > low {
+			ratio = (f.height - low) / (high - low)
+		}
+
+		c := hex(lerp(lowFloorColor, highFloorColor, ratio))
+		fmt.Fprintf(w, `<polygon points="%d,%d %d,%d %d,%d" fill="%s" stroke="%s" stroke-width="1"/>`+"\n",
+			f.points[0].X, f.points[0].Z, f.points[1].X, f.points[1].Z, f.points[2].X, f.points[2].Z, c, c,
+		)
+	}
+	fmt.Fprintln(w, `</g>`)
+}
+
+func writeRooms(w io.Writer, scene *rom.Scene, unit float64) {
+	fmt.Fprintln(w, `<g id="rooms" fill="none">`)
+	for _, room := range scene.Rooms {
+		b := room.MeshBounds
+		if b.Empty {
+			continue
+		}
+
+		fmt.Fprintf(w, `<rect x="%d" y="%d" width="%d" height="%d" stroke="%s" stroke-width="%.1f" stroke-dasharray="%.1f"><title>Room %d</title></rect>`+"\n",
+			b.Min.X, b.Min.Z, int(b.Max.X)-int(b.Min.X), int(b.Max.Z)-int(b.Min.Z),
+			hex(roomColor), unit/4, unit*2, room.ID,
+		)
+		writeLabel(w, float64(b.Min.X)+unit, float64(b.Min.Z)+unit*3, unit*2.5, fmt.Sprintf("Room %d", room.ID))
+	}
+	fmt.Fprintln(w, `</g>`)
+}
+
+func writePaths(w io.Writer, scene *rom.Scene, unit float64) {
+	fmt.Fprintln(w, `<g id="paths" fill="none">`)
+	for k, path := range scene.Paths {
+		fmt.Fprintf(w, `<polyline stroke="%s" stroke-width="%.1f" points="`, hex(pathColor), unit/2)
+		for _, p := range path.Points {
+			fmt.Fprintf(w, "%d,%d ", p.X, p.Z)
+		}
+		fmt.Fprintf(w, `"><title>Path %d</title></polyline>`+"\n", k)
+	}
+	fmt.Fprintln(w, `</g>`)
+}
+
+func writeDoors(w io.Writer, scene *rom.Scene, unit float64) {
+	fmt.Fprintln(w, `<g id="doors">`)
+	for k, door := range scene.TransitionActors {
+		title := fmt.Sprintf("Door %d: %s, rooms %d/%d", k, actorName(door.Description, door.ID&0x1FFF), door.FrontRoom, door.BackRoom)
+		fmt.Fprintf(w, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s" transform="rotate(%.1f %d %d)"><title>%s</title></rect>`+"\n",
+			float64(door.Position.X)-unit*1.5, float64(door.Position.Z)-unit/2, unit*3, unit,
+			hex(doorColor), -float64(door.RotationDegrees), door.Position.X, door.Position.Z,
+			html.EscapeString(title),
+		)
+	}
+	fmt.Fprintln(w, `</g>`)
+}
+
+func writeSpawns(w io.Writer, scene *rom.Scene, unit float64) {
+	fmt.Fprintln(w, `<g id="spawns">`)
+	for k, spawn := range scene.StartPositions {
+		x, z := float64(spawn.Position.X), float64(spawn.Position.Z)
+		fmt.Fprintf(w, `<polygon points="%.1f,%.1f %.1f,%.1f %.1f,%.1f %.1f,%.1f" fill="%s"><title>Spawn %d</title></polygon>`+"\n",
+			x, z-unit*1.5, x+unit*1.5, z, x, z+unit*1.5, x-unit*1.5, z,
+			hex(spawnColor), k,
+		)
+	}
+	fmt.Fprintln(w, `</g>`)
+}
+
+func writeActors(w io.Writer, scene *rom.Scene, unit float64) {
+	fmt.Fprintln(w, `<g id="actors">`)
+	for _, room := range scene.Rooms {
+		for k, actor := range room.ActorList {
+			name := actorName(actor.Description, actor.ID)
+			title := fmt.Sprintf("Room %d actor %d: %s", room.ID, k, name)
+			if actor.ParamsDescription != "" {
+				title += " (" + actor.ParamsDescription + ")"
+			}
+
+			fmt.Fprintf(w, `<circle cx="%d" cy="%d" r="%.1f" fill="%s"><title>%s</title></circle>`+"\n",
+				actor.Position.X, actor.Position.Z, unit, hex(actorColor), html.EscapeString(title),
+			)
+			writeLabel(w, float64(actor.Position.X)+unit*1.5, float64(actor.Position.Z)+unit/2, unit*1.5, name)
+		}
+	}
+	fmt.Fprintln(w, `</g>`)
+}
+
+func writeLabel(w io.Writer, x, y, size float64, text string) {
+	fmt.Fprintf(w, `<text x="%.1f" y="%.1f" font-size="%.1f" font-family="sans-serif" fill="%s">%s</text>`+"\n",
+		x, y, size, hex(labelColor), html.EscapeString(text),
+	)
+}
+
+func sceneTitle(scene *rom.Scene) string {
+	if scene.EntranceMessage == "" {
+		return scene.Name
+	}
+
+	return scene.Name + " - " + scene.EntranceMessage
+}
+
+// actorName returns the most readable name available for an actor.
+func actorName(desc rom.ActorDescription, id uint16) string {
+	switch {
+	case desc.Identification != "":
+		return desc.Identification
+	case desc.FileName != "":
+		return desc.FileName
+	default:
+		return fmt.Sprintf("0x%04X", id)
+	}
+}
+
+func lerp(a, b color.NRGBA, t float64) color.NRGBA {
+	mix := func(x, y uint8) uint8 {
+		return uint8(float64(x) + (float64(y)-float64(x))*t)
+	}
+
+	return color.NRGBA{mix(a.R, b.R), mix(a.G, b.G), mix(a.B, b.B), 0xFF}
+}
+
+func hex(c color.NRGBA) string {
+	return fmt.Sprintf("#%02X%02X%02X", c.R, c.G, c.B)
+}
@@ -0,0 +1,321 @@
+// Package search finds messages by content and header properties.
+package search
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/L-P/mme/rom"
+)
+
+// Pagination defaults
+const (
+	DefaultLimit = 50
+	MaxLimit     = 500
+)
+
+// snippetContext is the number of characters kept around a match.
+const snippetContext = 40
+
+// gramSize is the maximum length in runes of the word substrings indexed in
+// Index.grams.
+const gramSize = 3
+
+// An Index is an inverted index of words to the messages containing them.
+type Index struct {
+	messages []rom.Message
+	lower    []string            // lowercased message strings
+	words    map[string][]int    // word to message indices, sorted
+	grams    map[string][]string // word substring to the words containing it
+}
+
+// NewIndex indexes messages, the index must be rebuilt when they change.
+func NewIndex(messages []rom.Message) *Index {
+	idx := &Index{
+		messages: messages,
+		lower:    make([]string, len(messages), len(messages)),
+		words:    make(map[string][]int),
+		grams:    make(map[string][]string),
+	}
+
+	for k, msg := range messages {
+		idx.lower[k] = strings.ToLower(msg.String)
+
+		seen := make(map[string]bool)
+		for _, word := range splitWords(idx.lower[k]) {
+			if !seen[word] {
+				seen[word] = true
+				idx.words[word] = append(idx.words[word], k)
+			}
+		}
+	}
+
+	for word := range idx.words {
+		for _, gram := range grams(word) {
+			idx.grams[gram] = append(idx.grams[gram], word)
+		}
+	}
+
+	return idx
+}
+
+// grams returns the distinct substrings of one to gramSize runes of a word.
+func grams(word string) []string {
+	runes := []rune(word)
+	seen := make(map[string]bool)
+	ret := make([]string, 0, len(runes)*gramSize)
+	for start := range runes {
+		for end := start + 1; end <= len(runes) && end-start <= gramSize; end++ {
+			gram := string(runes[start:end])
+			if !seen[gram] {
+				seen[gram] = true
+				ret = append(ret, gram)
+			}
+		}
+	}
+
+	return ret
+}
+
+// wordsContaining returns the indexed words containing s. Short strings are
+// looked up directly, longer ones are checked against the words sharing their
+// least common gram.
+func (idx *Index) wordsContaining(s string) []string {
+	runes := []rune(s)
+	if len(runes) <= gramSize {
+		return idx.grams[s]
+	}
+
+	var smallest []string
+	for start := 0; start+gramSize <= len(runes); start++ {
+		words, ok := idx.grams[string(runes[start:start+gramSize])]
+		if !ok {
+			return nil
+		}
+		if smallest == nil || len(words) < len(smallest) {
+			smallest = words
+		}
+	}
+
+	ret := make([]string, 0, len(smallest))
+	for _, word := range smallest {
+		if strings.Contains(word, s) {
+			ret = append(ret, word)
+		}
+	}
+
+	return ret
+}
+
+func splitWords(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+// Query holds the search parameters, zero values disable filters.
+type Query struct {
+	Text  string // substring to find, case insensitive
+	Regex *regexp.Regexp
+
+	IDMin        uint16
+	IDMax        uint16 // 0 for no maximum
+	TextBoxType  *byte
+	Icon         *byte
+	Category     string // see rom.MessageRanges
+	HasRupeeCost bool
+	ControlCodes []string // markup names of codes that must all be present
+
+	Offset int
+	Limit  int
+}
+
+// A Result is a message matching a Query.
+type Result struct {
+	ID      uint16
+	Header  rom.MessageHeader
+	String  string
+	Snippet string
+	// Highlights are [start, end) byte offsets of the matches in Snippet.
+	Highlights [][2]int
+}
+
+// Results is a page of results.
+type Results struct {
+	Total   int
+	Offset  int
+	Limit   int
+	Results []Result
+}
+
+// Search returns the messages matching the query.
+func (idx *Index) Search(q Query) Results {
+	if q.Limit <= 0 {
+		q.Limit = DefaultLimit
+	}
+	if q.Limit > MaxLimit {
+		q.Limit = MaxLimit
+	}
+	if q.Offset < 0 {
+		q.Offset = 0
+	}
+
+	text := strings.ToLower(q.Text)
+	matches := make([]int, 0)
+	for _, k := range idx.candidates(text) {
+		if idx.filter(k, q) && (text == "" || strings.Contains(idx.lower[k], text)) {
+			if q.Regex == nil || q.Regex.MatchString(idx.messages[k].String) {
+				matches = append(matches, k)
+			}
+		}
+	}
+
+	results := Results{Total: len(matches), Offset: q.Offset, Limit: q.Limit}
+	results.Results = make([]Result, 0, q.Limit)
+	for i := q.Offset; i < len(matches) && i < q.Offset+q.Limit; i++ {
+		msg := &idx.messages[matches[i]]
+		result := Result{ID: msg.ID, Header: msg.MessageHeader, String: msg.String}
+		result.Snippet, result.Highlights = idx.snippet(matches[i], text, q.Regex)
+		results.Results = append(results.Results, result)
+	}
+
+	return results
+}
+
+// candidates uses the index to restrict the messages that may contain text.
+// Any word of the query that is a whole word in the text must appear in the
+// index as a word containing it, the first and last words may be partial.
+func (idx *Index) candidates(text string) []int {
+	all := func() []int {
+		ids := make([]int, len(idx.messages), len(idx.messages))
+		for k := range ids {
+			ids[k] = k
+		}
+		return ids
+	}
+
+	words := splitWords(text)
+	if len(words) == 0 {
+		return all()
+	}
+
+	// The longest word is the most selective.
+	longest := words[0]
+	for _, word := range words {
+		if len(word) > len(longest) {
+			longest = word
+		}
+	}
+
+	set := make(map[int]bool)
+	for _, word := range idx.wordsContaining(longest) {
+		for _, k := range idx.words[word] {
+			set[k] = true
+		}
+	}
+
+	candidates := make([]int, 0, len(set))
+	for k := range set {
+		candidates = append(candidates, k)
+	}
+	sort.Ints(candidates)
+
+	return candidates
+}
+
+func (idx *Index) filter(k int, q Query) bool {
+	msg := &idx.messages[k]
+
+	if msg.ID < q.IDMin || (q.IDMax > 0 && msg.ID > q.IDMax) {
+		return false
+	}
+	if q.TextBoxType != nil && msg.TextBoxType != *q.TextBoxType {
+		return false
+	}
+	if q.Icon != nil && msg.Icon != *q.Icon {
+		return false
+	}
+	if q.Category != "" && msg.Category != q.Category {
+		return false
+	}
+	if q.HasRupeeCost && !msg.HasRupeeCost() {
+		return false
+	}
+
+	for _, name := range q.ControlCodes {
+		found := false
+		for _, token := range msg.Tokens {
+			if token.Type != rom.MessageTokenText && token.Name == name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// snippet returns the part of the message around the first match and the
+// position of all matches within it.
+func (idx *Index) snippet(k int, text string, re *regexp.Regexp) (string, [][2]int) {
+	str := idx.messages[k].String
+	var matches [][2]int
+
+	switch {
+	case re != nil:
+		for _, m := range re.FindAllStringIndex(str, -1) {
+			matches = append(matches, [2]int{m[0], m[1]})
+		}
+	case text != "":
+		// Lowercasing keeps byte offsets for the characters messages use.
+		lower := idx.lower[k]
+		for start := 0; ; {
+			i := strings.Index(lower[start:], text)
+			if i < 0 {
+				break
+			}
+			matches = append(matches, [2]int{start + i, start + i + len(text)})
+			start += i + len(text)
+		}
+	}
+
+	if len(matches) == 0 {
+		end := snippetContext * 2
+		if end >= len(str) {
+			return str, nil
+		}
+		for end < len(str) && !utf8.RuneStart(str[end]) {
+			end++
+		}
+		return str[:end], nil
+	}
+
+	from, to := matches[0][0]-snippetContext, matches[0][1]+snippetContext
+	if from < 0 {
+		from = 0
+	}
+	if to > len(str) {
+		to = len(str)
+	}
+	for from > 0 && !utf8.RuneStart(str[from]) {
+		from--
+	}
+	for to < len(str) && !utf8.RuneStart(str[to]) {
+		to++
+	}
+
+	highlights := make([][2]int, 0, len(matches))
+	for _, m := range matches {
+		if m[0] >= from && m[1] <= to {
+			highlights = append(highlights, [2]int{m[0] - from, m[1] - from})
+		}
+	}
+
+	return str[from:to], highlights
+}
@@ -0,0 +1,44 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/L-P/mme/rom"
+	"github.com/husobee/vestigo"
+)
+
+func (s *Server) actorsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+
+	enc.Encode(s.rom.ActorSummaries())
+}
+
+func (s *Server) actorDetailHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(vestigo.Param(r, "id"), 0, 16)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	description, ok := rom.ActorDescriptions[uint16(id)]
+	if !ok {
+		description.ID = uint16(id)
+	}
+
+	occurrences := s.rom.ActorOccurrences()[uint16(id)]
+	if occurrences == nil {
+		occurrences = []rom.ActorOccurrence{}
+	}
+
+	w.Header().Add("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+
+	enc.Encode(struct {
+		rom.ActorDescription
+		Count       int
+		Occurrences []rom.ActorOccurrence
+	}{description, len(occurrences), occurrences})
+}
@@ -0,0 +1,26 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/L-P/mme/rom"
+)
+
+func (s *Server) collectiblesHandler(w http.ResponseWriter, r *http.Request) {
+	collectibles := s.rom.Collectibles()
+	if kind := rom.CollectibleKind(r.URL.Query().Get("kind")); kind != "" {
+		filtered := make([]rom.Collectible, 0, len(collectibles))
+		for _, c := range collectibles {
+			if c.Kind == kind {
+				filtered = append(filtered, c)
+			}
+		}
+		collectibles = filtered
+	}
+
+	w.Header().Add("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+
+	enc.Encode(collectibles)
+}
@@ -0,0 +1,226 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/L-P/mme/conversation"
+	"github.com/L-P/mme/rom"
+	"github.com/L-P/mme/textbox"
+	"github.com/L-P/mme/translation"
+	"github.com/husobee/vestigo"
+)
+
+func (s *Server) messagesHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.Encode(s.rom.Messages)
+}
+
+// messageEditHandler replaces a message, the body is a JSON object holding
+// the MessageHeader fields and the new Markup. Omitted header fields are kept.
+func (s *Server) messageEditHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(vestigo.Param(r, "id"), 0, 16)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	msg, err := s.rom.GetMessageByID(uint16(id))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	edit := struct {
+		rom.MessageHeader
+		Markup string
+	}{msg.MessageHeader, msg.Markup}
+	if err := json.NewDecoder(r.Body).Decode(&edit); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := s.rom.SetMessage(uint16(id), edit.MessageHeader, edit.Markup); err != nil {
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+	s.reindexMessages()
+
+	w.Header().Add("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.Encode(msg)
+}
+
+func (s *Server) patchedROMHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Content-Type", "application/octet-stream")
+	w.Header().Add("Content-Disposition", "attachment; filename=\"rom.z64\"")
+	if err := s.rom.WriteROM(w); err != nil {
+		log.Print(err)
+	}
+}
+
+func (s *Server) messagesExportHandler(w http.ResponseWriter, r *http.Request) {
+	format, err := translation.ParseFormat(vestigo.Param(r, "format"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Add("Content-Type", "application/octet-stream")
+	w.Header().Add("Content-Disposition", fmt.Sprintf("attachment; filename=\"messages.%s\"", format))
+	if err := translation.Export(w, s.rom.Messages, format); err != nil {
+		log.Print(err)
+	}
+}
+
+// messagesImportHandler validates and applies a translation file, nothing is
+// applied if any problem is found.
+func (s *Server) messagesImportHandler(w http.ResponseWriter, r *http.Request) {
+	format, err := translation.ParseFormat(vestigo.Param(r, "format"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	entries, err := translation.Import(r.Body, format)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Add("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+
+	problems := translation.Validate(entries, s.rom)
+	if len(problems) > 0 {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		enc.Encode(map[string]interface{}{"Problems": problems})
+		return
+	}
+
+	applied, err := translation.Apply(entries, s.rom)
+	s.reindexMessages()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	enc.Encode(map[string]interface{}{"Applied": applied})
+}
+
+func (s *Server) messagesOverflowsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.Encode(textbox.CheckAll(s.rom.Messages, s.rom.FontWidths))
+}
+
+func (s *Server) messageOverflowsHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(vestigo.Param(r, "id"), 0, 16)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	msg, err := s.rom.GetMessageByID(uint16(id))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Add("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.Encode(textbox.Check(msg, s.rom.FontWidths))
+}
+
+func (s *Server) messagePreviewHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(vestigo.Param(r, "id"), 0, 16)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	msg, err := s.rom.GetMessageByID(uint16(id))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/png")
+	font := textbox.Font{Widths: s.rom.FontWidths, Glyph: s.rom.Glyph}
+	if file, err := s.rom.GetFileByName("message_static"); err == nil {
+		font.Backgrounds = file.Data()
+	}
+	if err := textbox.Render(w, msg, font); err != nil {
+		log.Print(err)
+	}
+}
+
+// messageThreadHandler returns the whole conversation a message is part of.
+func (s *Server) messageThreadHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(vestigo.Param(r, "id"), 0, 16)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	nodes, edges := s.conversations().Thread(uint16(id))
+	if nodes == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Add("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.Encode(map[string]interface{}{
+		"Messages": nodes,
+		"Edges":    edges,
+	})
+}
+
+func (s *Server) messageThreadDOTHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(vestigo.Param(r, "id"), 0, 16)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	nodes, edges := s.conversations().Thread(uint16(id))
+	if nodes == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Add("Content-Type", "text/vnd.graphviz")
+	if err := conversation.WriteDOT(w, nodes, edges); err != nil {
+		log.Print(err)
+	}
+}
+
+func (s *Server) messagesGraphDOTHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Content-Type", "text/vnd.graphviz")
+	if err := s.conversations().WriteDOT(w); err != nil {
+		log.Print(err)
+	}
+}
+
+func (s *Server) messageReferencesHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(vestigo.Param(r, "id"), 0, 16)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	refs, err := s.rom.MessageReferences(uint16(id))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Add("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.Encode(refs)
+}
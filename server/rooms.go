@@ -2,10 +2,14 @@ package server
 
 import (
 	"encoding/json"
+	"fmt"
 	"log"
 	"net/http"
 	"strconv"
 
+	"github.com/L-P/mme/minimap"
+	"github.com/L-P/mme/rom"
+	"github.com/L-P/mme/swatch"
 	"github.com/husobee/vestigo"
 )
 
@@ -22,8 +26,157 @@ func (s *Server) roomDetailHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if day := r.URL.Query().Get("day"); day != "" {
+		n, err := strconv.Atoi(day)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		halfDay, ok := rom.NewHalfDay(n, r.URL.Query().Get("night") == "true")
+		if !ok {
+			http.Error(w, "day out of range", http.StatusBadRequest)
+			return
+		}
+
+		filtered := *room
+		filtered.ActorList = room.ActorsAt(halfDay)
+		room = &filtered
+	}
+
 	w.Header().Add("Content-Type", "application/json")
 	enc := json.NewEncoder(w)
 
 	enc.Encode(room)
 }
+
+func (s *Server) roomMapHandler(w http.ResponseWriter, r *http.Request) {
+	start, err := strconv.ParseInt(vestigo.Param(r, "start"), 10, 32)
+	if err != nil {
+		log.Print(err)
+		return
+	}
+
+	room, err := s.rom.GetRoomByVROMStart(uint32(start))
+	if err != nil {
+		log.Print(err)
+		return
+	}
+
+	var textures []byte
+	if file, err := s.rom.GetFileByName("map_i_static"); err != nil {
+		log.Print("map_i_static not found, drawing map without textures")
+	} else if file.Size()%minimap.TextureSize != 0 {
+		log.Printf("map_i_static size 0x%X is not a multiple of a map texture size", file.Size())
+	} else {
+		textures = file.Data()
+	}
+
+	w.Header().Set("Content-Type", "image/png")
+	if err := minimap.Generate(w, room, textures); err != nil {
+		log.Print(err)
+	}
+}
+
+func (s *Server) roomLightsSwatchHandler(w http.ResponseWriter, r *http.Request) {
+	start, err := strconv.ParseInt(vestigo.Param(r, "start"), 10, 32)
+	if err != nil {
+		log.Print(err)
+		return
+	}
+
+	room, err := s.rom.GetRoomByVROMStart(uint32(start))
+	if err != nil {
+		log.Print(err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/png")
+	if err := swatch.GenerateLights(w, room.Lights); err != nil {
+		log.Print(err)
+	}
+}
+
+// editRoomActors applies an edit to the actor list of the room and saves it,
+// the list is restored if the edit fails. It replies with the room, its
+// VROMStart changes if its file had to be moved.
+func (s *Server) editRoomActors(w http.ResponseWriter, r *http.Request, edit func(*rom.Room) error) {
+	start, err := strconv.ParseInt(vestigo.Param(r, "start"), 10, 32)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	room, err := s.rom.GetRoomByVROMStart(uint32(start))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	backup := append([]rom.ActorEntry(nil), room.ActorList...)
+	if err := edit(room); err != nil {
+		room.ActorList = backup
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+
+	if err := s.rom.SaveRoomActors(room); err != nil {
+		room.ActorList = backup
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+
+	w.Header().Add("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.Encode(room)
+}
+
+func (s *Server) roomActorsReplaceHandler(w http.ResponseWriter, r *http.Request) {
+	s.editRoomActors(w, r, func(room *rom.Room) error {
+		var actors []rom.ActorEntry
+		if err := json.NewDecoder(r.Body).Decode(&actors); err != nil {
+			return err
+		}
+
+		return room.SetActors(actors)
+	})
+}
+
+func (s *Server) roomActorAddHandler(w http.ResponseWriter, r *http.Request) {
+	s.editRoomActors(w, r, func(room *rom.Room) error {
+		var actor rom.ActorEntry
+		if err := json.NewDecoder(r.Body).Decode(&actor); err != nil {
+			return err
+		}
+
+		_, err := room.AddActor(actor)
+		return err
+	})
+}
+
+func (s *Server) roomActorEditHandler(w http.ResponseWriter, r *http.Request) {
+	s.editRoomActors(w, r, func(room *rom.Room) error {
+		k, err := strconv.Atoi(vestigo.Param(r, "index"))
+		if err != nil || k < 0 || k >= len(room.ActorList) {
+			return fmt.Errorf("invalid actor index %s", vestigo.Param(r, "index"))
+		}
+
+		actor := room.ActorList[k]
+		if err := json.NewDecoder(r.Body).Decode(&actor); err != nil {
+			return err
+		}
+
+		return room.SetActor(k, actor)
+	})
+}
+
+func (s *Server) roomActorDeleteHandler(w http.ResponseWriter, r *http.Request) {
+	s.editRoomActors(w, r, func(room *rom.Room) error {
+		k, err := strconv.Atoi(vestigo.Param(r, "index"))
+		if err != nil {
+			return err
+		}
+
+		return room.DeleteActor(k)
+	})
+}
@@ -2,10 +2,14 @@ package server
 
 import (
 	"encoding/json"
+	"fmt"
 	"log"
 	"net/http"
 	"strconv"
 
+	"github.com/L-P/mme/rom"
+	"github.com/L-P/mme/scenemap"
+	"github.com/L-P/mme/swatch"
 	"github.com/husobee/vestigo"
 )
 
@@ -28,8 +32,163 @@ func (s *Server) sceneDetailHandler(w http.ResponseWriter, r *http.Request) {
 	enc.Encode(scene)
 }
 
+func (s *Server) sceneCamerasHandler(w http.ResponseWriter, r *http.Request) {
+	start, err := strconv.ParseInt(vestigo.Param(r, "start"), 10, 32)
+	if err != nil {
+		log.Print(err)
+		return
+	}
+
+	scene, err := s.rom.GetSceneByVROMStart(uint32(start))
+	if err != nil {
+		log.Print(err)
+		return
+	}
+
+	w.Header().Add("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+
+	enc.Encode(map[string]interface{}{
+		"Collision": scene.Collision.Cameras,
+		"Actors":    scene.Cameras,
+	})
+}
+
+func (s *Server) sceneEnvironmentSwatchHandler(w http.ResponseWriter, r *http.Request) {
+	start, err := strconv.ParseInt(vestigo.Param(r, "start"), 10, 32)
+	if err != nil {
+		log.Print(err)
+		return
+	}
+
+	index, err := strconv.ParseInt(vestigo.Param(r, "index"), 10, 32)
+	if err != nil {
+		log.Print(err)
+		return
+	}
+
+	scene, err := s.rom.GetSceneByVROMStart(uint32(start))
+	if err != nil {
+		log.Print(err)
+		return
+	}
+
+	if index < 0 || int(index) >= len(scene.EnvironmentSettings) {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/png")
+	if err := swatch.Generate(w, scene.EnvironmentSettings[index]); err != nil {
+		log.Print(err)
+	}
+}
+
 func (s *Server) scenesHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Add("Content-Type", "application/json")
 	enc := json.NewEncoder(w)
 	enc.Encode(s.rom.Scenes)
 }
+
+func (s *Server) sceneFlagsHandler(w http.ResponseWriter, r *http.Request) {
+	start, err := strconv.ParseInt(vestigo.Param(r, "start"), 10, 32)
+	if err != nil {
+		log.Print(err)
+		return
+	}
+
+	scene, err := s.rom.GetSceneByVROMStart(uint32(start))
+	if err != nil {
+		log.Print(err)
+		return
+	}
+
+	w.Header().Add("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+
+	enc.Encode(s.rom.SceneFlags(scene))
+}
+
+// sceneMemoryHandler returns the memory estimates of a scene rooms, limits
+// can be overridden with the "actor_heap", "object_space" and "threshold"
+// parameters.
+func (s *Server) sceneMemoryHandler(w http.ResponseWriter, r *http.Request) {
+	start, err := strconv.ParseInt(vestigo.Param(r, "start"), 10, 32)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	scene, err := s.rom.GetSceneByVROMStart(uint32(start))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	limits := rom.SceneMemoryLimits(scene)
+	params := r.URL.Query()
+	for name, limit := range map[string]*uint32{
+		"actor_heap":   &limits.ActorHeap,
+		"object_space": &limits.ObjectSpace,
+	} {
+		if v := params.Get(name); v != "" {
+			n, err := strconv.ParseUint(v, 0, 32)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("invalid %s: %s", name, v), http.StatusBadRequest)
+				return
+			}
+			*limit = uint32(n)
+		}
+	}
+
+	if v := params.Get("threshold"); v != "" {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil || f <= 0 {
+			http.Error(w, fmt.Sprintf("invalid threshold: %s", v), http.StatusBadRequest)
+			return
+		}
+		limits.Threshold = f
+	}
+
+	w.Header().Add("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.Encode(s.rom.SceneMemory(scene, limits))
+}
+
+func (s *Server) sceneMapHandler(w http.ResponseWriter, r *http.Request) {
+	start, err := strconv.ParseInt(vestigo.Param(r, "start"), 10, 32)
+	if err != nil {
+		log.Print(err)
+		return
+	}
+
+	scene, err := s.rom.GetSceneByVROMStart(uint32(start))
+	if err != nil {
+		log.Print(err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/svg+xml")
+	if err := scenemap.Generate(w, scene); err != nil {
+		log.Print(err)
+	}
+}
+
+func (s *Server) sceneLightsSwatchHandler(w http.ResponseWriter, r *http.Request) {
+	start, err := strconv.ParseInt(vestigo.Param(r, "start"), 10, 32)
+	if err != nil {
+		log.Print(err)
+		return
+	}
+
+	scene, err := s.rom.GetSceneByVROMStart(uint32(start))
+	if err != nil {
+		log.Print(err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/png")
+	if err := swatch.GenerateLights(w, scene.Lights); err != nil {
+		log.Print(err)
+	}
+}
@@ -0,0 +1,122 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/L-P/mme/conversation"
+	"github.com/L-P/mme/rom"
+	"github.com/L-P/mme/search"
+)
+
+// reindexMessages rebuilds the message search index and conversation graph,
+// it must be called after messages are modified.
+func (s *Server) reindexMessages() {
+	index := search.NewIndex(s.rom.Messages)
+	graph := conversation.Build(s.rom.Messages)
+
+	s.messageIndexMutex.Lock()
+	defer s.messageIndexMutex.Unlock()
+	s.messageIndex = index
+	s.messageGraph = graph
+}
+
+// conversations returns the graph built by the last reindexMessages call.
+func (s *Server) conversations() *conversation.Graph {
+	s.messageIndexMutex.Lock()
+	defer s.messageIndexMutex.Unlock()
+	return s.messageGraph
+}
+
+// messagesSearchHandler searches messages, parameters are:
+//   - q: case-insensitive substring
+//   - regex: regular expression
+//   - id_min, id_max: message ID range, inclusive
+//   - box_type, icon: MessageHeader values
+//   - rupee_cost: set to only return messages with a price
+//   - codes: comma-separated control code names, as in the markup
+//   - offset, limit: pagination
+func (s *Server) messagesSearchHandler(w http.ResponseWriter, r *http.Request) {
+	query, err := parseSearchQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.messageIndexMutex.Lock()
+	index := s.messageIndex
+	s.messageIndexMutex.Unlock()
+
+	w.Header().Add("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.Encode(index.Search(query))
+}
+
+func parseSearchQuery(r *http.Request) (search.Query, error) {
+	params := r.URL.Query()
+	query := search.Query{Text: params.Get("q")}
+
+	if v := params.Get("regex"); v != "" {
+		re, err := regexp.Compile(v)
+		if err != nil {
+			return query, err
+		}
+		query.Regex = re
+	}
+
+	for name, dst := range map[string]*uint16{"id_min": &query.IDMin, "id_max": &query.IDMax} {
+		if v := params.Get(name); v != "" {
+			id, err := strconv.ParseUint(v, 0, 16)
+			if err != nil {
+				return query, err
+			}
+			*dst = uint16(id)
+		}
+	}
+
+	for name, param := range map[string]struct {
+		dst   **byte
+		names map[byte]string
+	}{
+		"box_type": {&query.TextBoxType, rom.TextBoxTypeNames},
+		"icon":     {&query.Icon, rom.IconNames},
+	} {
+		if v := params.Get(name); v != "" {
+			value, err := parseNamedByte(v, param.names)
+			if err != nil {
+				return query, err
+			}
+			*param.dst = &value
+		}
+	}
+
+	query.Category = params.Get("category")
+
+	query.HasRupeeCost = params.Get("rupee_cost") != ""
+	if v := params.Get("codes"); v != "" {
+		query.ControlCodes = strings.Split(v, ",")
+	}
+
+	query.Offset, _ = strconv.Atoi(params.Get("offset"))
+	query.Limit, _ = strconv.Atoi(params.Get("limit"))
+
+	return query, nil
+}
+
+// parseNamedByte parses a byte given either as a number or as its name.
+func parseNamedByte(v string, names map[byte]string) (byte, error) {
+	if value, ok := rom.LookupName(names, v); ok {
+		return value, nil
+	}
+
+	b, err := strconv.ParseUint(v, 0, 8)
+	if err != nil {
+		return 0, fmt.Errorf("unknown value %s", v)
+	}
+
+	return byte(b), nil
+}
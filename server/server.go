@@ -6,10 +6,13 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/L-P/mme/colormap"
+	"github.com/L-P/mme/conversation"
 	"github.com/L-P/mme/rom"
+	"github.com/L-P/mme/search"
 	"github.com/gobuffalo/packr"
 	"github.com/husobee/vestigo"
 )
@@ -20,6 +23,10 @@ type Server struct {
 	rom        *rom.View
 	static     packr.Box
 	router     *vestigo.Router
+
+	messageIndex      *search.Index
+	messageGraph      *conversation.Graph
+	messageIndexMutex sync.Mutex
 }
 
 // New creates a new Server
@@ -39,6 +46,7 @@ func New(view *rom.View) *Server {
 		router: router,
 	}
 
+	s.reindexMessages()
 	s.setupRoutes()
 
 	return s
@@ -57,18 +65,50 @@ func (s *Server) setupRoutes() {
 			"http://localhost:8064",
 			"http://localhost:8080",
 		},
+		AllowMethods: []string{"GET", "POST", "PUT", "DELETE"},
+		AllowHeaders: []string{"Content-Type"},
 	})
 
-	s.router.Get("/api/rom", s.romHandler)
-	s.router.Get("/api/colormap", s.colormapHandler())
-	s.router.Get("/api/messages", s.messagesHandler)
-
-	s.router.Get("/api/rooms/:start", s.roomDetailHandler)
-	s.router.Get("/api/scenes/:start", s.sceneDetailHandler)
-	s.router.Get("/api/scenes", s.scenesHandler)
-
-	s.router.Get("/api/files/:start", s.fileDataHandler)
-	s.router.Get("/api/files", s.filesHandler)
+	s.router.Get("/api/rom", s.reading(s.romHandler))
+	s.router.Get("/api/rom/patched.z64", s.reading(s.patchedROMHandler))
+	s.router.Get("/api/colormap", s.reading(s.colormapHandler()))
+	s.router.Get("/api/messages", s.reading(s.messagesHandler))
+	s.router.Get("/api/messages/export/:format", s.reading(s.messagesExportHandler))
+	s.router.Post("/api/messages/import/:format", s.editing(s.messagesImportHandler))
+	s.router.Get("/api/messages/search", s.reading(s.messagesSearchHandler))
+	s.router.Get("/api/messages/overflows", s.reading(s.messagesOverflowsHandler))
+	s.router.Get("/api/messages/graph.dot", s.reading(s.messagesGraphDOTHandler))
+	s.router.Get("/api/messages/:id/thread", s.reading(s.messageThreadHandler))
+	s.router.Get("/api/messages/:id/thread.dot", s.reading(s.messageThreadDOTHandler))
+	s.router.Get("/api/messages/:id/overflows", s.reading(s.messageOverflowsHandler))
+	s.router.Get("/api/messages/:id/preview.png", s.reading(s.messagePreviewHandler))
+	s.router.Get("/api/messages/:id/references", s.reading(s.messageReferencesHandler))
+	s.router.Put("/api/messages/:id", s.editing(s.messageEditHandler))
+
+	s.router.Get("/api/collectibles", s.reading(s.collectiblesHandler))
+	s.router.Get("/api/actors/:id", s.reading(s.actorDetailHandler))
+	s.router.Get("/api/actors", s.reading(s.actorsHandler))
+
+	s.router.Get("/api/rooms/:start/map.png", s.reading(s.roomMapHandler))
+	s.router.Get("/api/rooms/:start/lights/swatch.png", s.reading(s.roomLightsSwatchHandler))
+	s.router.Put("/api/rooms/:start/actors", s.editing(s.roomActorsReplaceHandler))
+	s.router.Post("/api/rooms/:start/actors", s.editing(s.roomActorAddHandler))
+	s.router.Put("/api/rooms/:start/actors/:index", s.editing(s.roomActorEditHandler))
+	s.router.Delete("/api/rooms/:start/actors/:index", s.editing(s.roomActorDeleteHandler))
+	s.router.Get("/api/rooms/:start", s.reading(s.roomDetailHandler))
+	s.router.Get("/api/scenes/:start/cameras", s.reading(s.sceneCamerasHandler))
+	s.router.Get("/api/scenes/:start/flags", s.reading(s.sceneFlagsHandler))
+	s.router.Get("/api/scenes/:start/map.svg", s.reading(s.sceneMapHandler))
+	s.router.Get("/api/scenes/:start/memory", s.reading(s.sceneMemoryHandler))
+	s.router.Get("/api/scenes/:start/query", s.reading(s.sceneQueryHandler))
+	s.router.Get("/api/scenes/:start/environments/:index/swatch.png", s.reading(s.sceneEnvironmentSwatchHandler))
+	s.router.Get("/api/scenes/:start/lights/swatch.png", s.reading(s.sceneLightsSwatchHandler))
+	s.router.Get("/api/scenes/:start/texture-animations/:index/preview.png", s.reading(s.textureAnimationPreviewHandler))
+	s.router.Get("/api/scenes/:start", s.reading(s.sceneDetailHandler))
+	s.router.Get("/api/scenes", s.reading(s.scenesHandler))
+
+	s.router.Get("/api/files/:start", s.reading(s.fileDataHandler))
+	s.router.Get("/api/files", s.reading(s.filesHandler))
 
 	// Static and generated files
 	s.router.Get("/", s.indexHandler)
@@ -79,6 +119,24 @@ func (s *Server) setupRoutes() {
 	s.router.Handle("/_/:type/:file", s.handleStatic())
 }
 
+// reading wraps a handler reading ROM data so it does not run during edits.
+func (s *Server) reading(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.rom.RLock()
+		defer s.rom.RUnlock()
+		h(w, r)
+	}
+}
+
+// editing wraps a handler modifying ROM data so it has exclusive access.
+func (s *Server) editing(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.rom.Lock()
+		defer s.rom.Unlock()
+		h(w, r)
+	}
+}
+
 func (s *Server) handleStatic() http.Handler {
 	return s.addCacheHeaders(http.FileServer(s.static))
 }
@@ -118,12 +176,6 @@ func (s *Server) addCacheHeaders(h http.Handler) http.HandlerFunc {
 	}
 }
 
-func (s *Server) messagesHandler(w http.ResponseWriter, r *http.Request) {
-	w.Header().Add("Content-Type", "application/json")
-	enc := json.NewEncoder(w)
-	enc.Encode(s.rom.Messages)
-}
-
 func (s *Server) romHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Add("Content-Type", "application/json")
 	enc := json.NewEncoder(w)
@@ -0,0 +1,128 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/L-P/mme/rom"
+	"github.com/husobee/vestigo"
+)
+
+// sceneQueryHandler answers spatial queries over a scene, the query kind is
+// given by the "type" parameter:
+//   - radius: actors within "r" of "x", "y", "z"
+//   - nearest: actor of ID "id" nearest to "x", "y", "z"
+//   - bounds: bounds of the contents of room "room" (room ID)
+//   - room: room containing "x", "y", "z"
+//
+// radius and nearest accept "setup" to restrict results to a single setup.
+func (s *Server) sceneQueryHandler(w http.ResponseWriter, r *http.Request) {
+	start, err := strconv.ParseInt(vestigo.Param(r, "start"), 10, 32)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	scene, err := s.rom.GetSceneByVROMStart(uint32(start))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	result, err := sceneQuery(scene, r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Add("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.Encode(result)
+}
+
+func sceneQuery(scene *rom.Scene, params url.Values) (interface{}, error) {
+	setup := -1
+	if v := params.Get("setup"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, err
+		}
+		setup = n
+	}
+
+	switch params.Get("type") {
+	case "radius":
+		p, err := parsePoint(params)
+		if err != nil {
+			return nil, err
+		}
+
+		radius, err := strconv.ParseFloat(params.Get("r"), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid radius: %s", err)
+		}
+
+		return scene.ActorsWithinRadius(p, radius, setup), nil
+	case "nearest":
+		p, err := parsePoint(params)
+		if err != nil {
+			return nil, err
+		}
+
+		id, err := strconv.ParseUint(params.Get("id"), 0, 16)
+		if err != nil {
+			return nil, fmt.Errorf("invalid actor ID: %s", err)
+		}
+
+		hit, ok := scene.NearestActor(p, uint16(id), setup)
+		if !ok {
+			return nil, nil
+		}
+		return hit, nil
+	case "bounds":
+		id, err := strconv.ParseUint(params.Get("room"), 0, 8)
+		if err != nil {
+			return nil, fmt.Errorf("invalid room ID: %s", err)
+		}
+		if int(id) >= len(scene.Rooms) {
+			return nil, errors.New("room not found")
+		}
+
+		return scene.Rooms[id].ContentsBounds(), nil
+	case "room":
+		p, err := parsePoint(params)
+		if err != nil {
+			return nil, err
+		}
+
+		room, ok := scene.RoomAt(p)
+		if !ok {
+			return nil, nil
+		}
+		return struct {
+			ID         byte
+			VROMStart  uint32
+			MeshBounds rom.Bounds
+		}{room.ID, room.VROMStart, room.MeshBounds}, nil
+	}
+
+	return nil, fmt.Errorf("unknown query type %q", params.Get("type"))
+}
+
+// parsePoint reads a point from the "x", "y" and "z" parameters.
+func parsePoint(params url.Values) (rom.Vec3, error) {
+	var p rom.Vec3
+	for name, dst := range map[string]*int16{"x": &p.X, "y": &p.Y, "z": &p.Z} {
+		v, err := strconv.ParseInt(params.Get(name), 10, 16)
+		if err != nil {
+			return p, fmt.Errorf("invalid %s: %s", name, err)
+		}
+		*dst = int16(v)
+	}
+
+	return p, nil
+}
@@ -0,0 +1,162 @@
+package server
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/L-P/mme/rom"
+	"github.com/L-P/mme/texture"
+	"github.com/husobee/vestigo"
+)
+
+const (
+	colorFrameSide   = 16  // size of a single color animation frame preview
+	maxPreviewFrames = 256 // some animations are thousands of frames long
+)
+
+// textureAnimationPreviewHandler renders every frame of a scene texture
+// animation side by side. Texture cycles need the texture format and size
+// passed as query parameters (eg. ?format=rgba16&width=32&height=32) as they
+// are only known by the display lists using them. Color animation frames show
+// the primitive color, and the environment color in their bottom half when it
+// is animated.
+func (s *Server) textureAnimationPreviewHandler(w http.ResponseWriter, r *http.Request) {
+	start, err := strconv.ParseInt(vestigo.Param(r, "start"), 10, 32)
+	if err != nil {
+		log.Print(err)
+		return
+	}
+
+	index, err := strconv.ParseInt(vestigo.Param(r, "index"), 10, 32)
+	if err != nil {
+		log.Print(err)
+		return
+	}
+
+	scene, err := s.rom.GetSceneByVROMStart(uint32(start))
+	if err != nil {
+		log.Print(err)
+		return
+	}
+
+	if index < 0 || int(index) >= len(scene.TextureAnimations) {
+		http.NotFound(w, r)
+		return
+	}
+
+	anim := &scene.TextureAnimations[index]
+	var frames []image.Image
+	switch {
+	case anim.Color != nil:
+		frames = colorAnimationFrames(anim)
+	case anim.Cycle != nil:
+		frames, err = s.textureCycleFrames(scene, anim, r)
+	}
+
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if len(frames) == 0 {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/png")
+	if err := png.Encode(w, texture.Strip(frames)); err != nil {
+		log.Print(err)
+	}
+}
+
+func colorAnimationFrames(anim *rom.TextureAnimation) []image.Image {
+	count := anim.Frames()
+	if count > maxPreviewFrames {
+		count = maxPreviewFrames
+	}
+
+	frames := make([]image.Image, count, count)
+	for i := range frames {
+		c := anim.Color.PrimColorAt(i)
+		img := image.NewNRGBA(image.Rect(0, 0, colorFrameSide, colorFrameSide))
+		draw.Draw(
+			img,
+			img.Bounds(),
+			&image.Uniform{color.NRGBA{R: c.R, G: c.G, B: c.B, A: c.A}},
+			image.Point{},
+			draw.Src,
+		)
+
+		// Animated environment colors are shown in the bottom half.
+		if env, ok := anim.Color.EnvColorAt(i); ok {
+			draw.Draw(
+				img,
+				image.Rect(0, colorFrameSide/2, colorFrameSide, colorFrameSide),
+				&image.Uniform{color.NRGBA{R: env.R, G: env.G, B: env.B, A: env.A}},
+				image.Point{},
+				draw.Src,
+			)
+		}
+
+		frames[i] = img
+	}
+
+	return frames
+}
+
+func (s *Server) textureCycleFrames(scene *rom.Scene, anim *rom.TextureAnimation, r *http.Request) ([]image.Image, error) {
+	format, err := texture.ParseFormat(r.URL.Query().Get("format"))
+	if err != nil {
+		return nil, err
+	}
+
+	width, _ := strconv.Atoi(r.URL.Query().Get("width"))
+	height, _ := strconv.Atoi(r.URL.Query().Get("height"))
+	size := format.Size(width, height)
+	if size <= 0 {
+		return nil, errors.New("width and height are required")
+	}
+
+	file, err := s.rom.GetFileByVROMStart(scene.VROMStart)
+	if err != nil {
+		return nil, err
+	}
+	data := file.Data()
+
+	count := anim.Frames()
+	if count > maxPreviewFrames {
+		count = maxPreviewFrames
+	}
+
+	frames := make([]image.Image, 0, count)
+	for i := 0; i < count; i++ {
+		address, ok := anim.Cycle.TextureAt(i)
+		if !ok {
+			continue
+		}
+
+		// Only textures stored in the scene file itself can be previewed.
+		if address>>24 != 0x02 {
+			return nil, errors.New("texture is not in the scene segment")
+		}
+
+		offset := int(address & 0x00FFFFFF)
+		if offset+size > len(data) {
+			return nil, errors.New("texture is out of the scene file bounds")
+		}
+
+		img, err := texture.Decode(data[offset:offset+size], format, width, height)
+		if err != nil {
+			return nil, err
+		}
+		frames = append(frames, img)
+	}
+
+	return frames, nil
+}
@@ -0,0 +1,53 @@
+package swatch
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+
+	"github.com/L-P/mme/rom"
+)
+
+const side = 64 // size of a single color square
+
+// Generate creates a swatch of an environment setting colors.
+// From left to right: ambient, light 1, light 2, fog.
+func Generate(w io.Writer, setting rom.EnvironmentSetting) error {
+	return generate(w, []rom.Color{
+		setting.AmbientColor,
+		setting.Light1Color,
+		setting.Light2Color,
+		setting.FogColor,
+	})
+}
+
+// GenerateLights creates a swatch of the colors of a light list, in order.
+func GenerateLights(w io.Writer, lights []rom.Light) error {
+	colors := make([]rom.Color, len(lights), len(lights))
+	for k, light := range lights {
+		colors[k] = light.LightColor()
+	}
+
+	return generate(w, colors)
+}
+
+func generate(w io.Writer, colors []rom.Color) error {
+	if len(colors) == 0 {
+		colors = []rom.Color{{}}
+	}
+
+	img := image.NewNRGBA(image.Rect(0, 0, side*len(colors), side))
+	for k, c := range colors {
+		draw.Draw(
+			img,
+			image.Rect(k*side, 0, (k+1)*side, side),
+			&image.Uniform{color.NRGBA{R: c.R, G: c.G, B: c.B, A: 255}},
+			image.Point{},
+			draw.Src,
+		)
+	}
+
+	return png.Encode(w, img)
+}
@@ -0,0 +1,42 @@
+package textbox
+
+import (
+	"image"
+
+	"github.com/L-P/mme/texture"
+)
+
+// Text box backgrounds are I4 textures in message_static covering the left
+// half of the box, the game draws them a second time mirrored and tints them
+// with the box color.
+const (
+	backgroundWidth  = 128
+	backgroundHeight = 64
+	backgroundFormat = texture.I4
+)
+
+// backgroundOffsets maps a TextBoxType to its texture offset in
+// message_static, types not listed are drawn as a flat color.
+var backgroundOffsets = map[byte]int{
+	0x00: 0x0000, // default, black
+	0x01: 0x1000, // wooden sign
+	0x02: 0x0000, // default, tinted blue
+	0x03: 0x2000, // ocarina staff
+}
+
+// background returns the decoded background texture of a box type, nil if
+// it has none or it is not available.
+func background(data []byte, boxType byte) *image.NRGBA {
+	offset, ok := backgroundOffsets[boxType]
+	size := backgroundFormat.Size(backgroundWidth, backgroundHeight)
+	if !ok || offset+size > len(data) {
+		return nil
+	}
+
+	tex, err := texture.Decode(data[offset:offset+size], backgroundFormat, backgroundWidth, backgroundHeight)
+	if err != nil {
+		return nil
+	}
+
+	return tex
+}
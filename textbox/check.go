@@ -0,0 +1,76 @@
+package textbox
+
+import (
+	"fmt"
+
+	"github.com/L-P/mme/rom"
+)
+
+// An Overflow is a line too wide or a box with too many lines.
+type Overflow struct {
+	MessageID uint16
+	Box       int // 0-indexed
+	Line      int // 0-indexed, -1 when the box has too many lines
+	Width     float32
+	MaxWidth  float32
+	Lines     int
+	MaxLines  int
+	Text      string
+}
+
+func (o Overflow) String() string {
+	if o.Line < 0 {
+		return fmt.Sprintf(
+			"message 0x%04X box #%d: %d lines, max %d",
+			o.MessageID, o.Box, o.Lines, o.MaxLines,
+		)
+	}
+
+	return fmt.Sprintf(
+		"message 0x%04X box #%d line #%d: %.0fpx, max %.0fpx: %q",
+		o.MessageID, o.Box, o.Line, o.Width, o.MaxWidth, o.Text,
+	)
+}
+
+// Check returns every overflow of a message.
+func Check(msg *rom.Message, widths rom.FontWidths) []Overflow {
+	limits := LimitsFor(msg)
+	overflows := make([]Overflow, 0)
+
+	for b, box := range Layout(msg, widths) {
+		if len(box.Lines) > limits.MaxLines {
+			overflows = append(overflows, Overflow{
+				MessageID: msg.ID,
+				Box:       b,
+				Line:      -1,
+				Lines:     len(box.Lines),
+				MaxLines:  limits.MaxLines,
+			})
+		}
+
+		for l, line := range box.Lines {
+			if line.Width > limits.MaxWidth {
+				overflows = append(overflows, Overflow{
+					MessageID: msg.ID,
+					Box:       b,
+					Line:      l,
+					Width:     line.Width,
+					MaxWidth:  limits.MaxWidth,
+					Text:      line.Text,
+				})
+			}
+		}
+	}
+
+	return overflows
+}
+
+// CheckAll returns every overflow of every message.
+func CheckAll(messages []rom.Message, widths rom.FontWidths) []Overflow {
+	overflows := make([]Overflow, 0)
+	for k := range messages {
+		overflows = append(overflows, Check(&messages[k], widths)...)
+	}
+
+	return overflows
+}
@@ -0,0 +1,146 @@
+// Package textbox approximates how the game lays out messages in text boxes.
+package textbox
+
+import (
+	"strings"
+
+	"github.com/L-P/mme/rom"
+)
+
+// Scale is the factor applied to the font widths when the game renders text.
+const Scale = 0.75
+
+// noIcon is the MessageHeader.Icon value of messages without an icon.
+const noIcon = 0xFE
+
+// iconIndent is how much the text is pushed to the right when the message
+// displays an icon, in pixels.
+const iconIndent = 32
+
+// fallbackWidth is used for characters missing from the width table.
+const fallbackWidth = 16
+
+// Limits are the text area dimensions of a text box.
+type Limits struct {
+	MaxWidth float32 // in pixels
+	MaxLines int
+}
+
+// DefaultLimits are the limits of the standard text box.
+var DefaultLimits = Limits{MaxWidth: 200, MaxLines: 4}
+
+// BoxLimits overrides DefaultLimits for specific MessageHeader.TextBoxType,
+// see rom.TextBoxTypeNames.
+var BoxLimits = map[byte]Limits{
+	0x03: {MaxWidth: 200, MaxLines: 2}, // ocarina, the staff takes the bottom half
+	0x04: {MaxWidth: 256, MaxLines: 4}, // no box, text can span the screen
+	0x05: {MaxWidth: 256, MaxLines: 4},
+	0x0D: {MaxWidth: 168, MaxLines: 3}, // Bombers' Notebook, next to the portrait
+}
+
+// variablePlaceholders is the text used to measure variables, they are
+// chosen to be on the wide side of what the game can display.
+var variablePlaceholders = map[string]string{
+	"name":             "MMMMMMMM",
+	"highscore":        "9999",
+	"time":             "00:00",
+	"time:moon-crash":  "00:00",
+	"time:speed":       "00:00",
+	"rupees:selected":  "999",
+	"rupees:total":     "999",
+	"points:tens":      "99",
+	"points:thousands": "9999",
+}
+
+// defaultVariablePlaceholder is used for variables not in
+// variablePlaceholders, mostly timers and codes.
+const defaultVariablePlaceholder = "00'00\"00"
+
+// A Line is a single line of text within a Box.
+type Line struct {
+	Width float32 // in pixels, once scaled
+	Text  string  // human-readable content
+}
+
+// A Box is a single text box, the game switches to the next one on box breaks.
+type Box struct {
+	Lines []Line
+}
+
+// LimitsFor returns the limits applying to a message.
+func LimitsFor(msg *rom.Message) Limits {
+	limits, ok := BoxLimits[msg.TextBoxType]
+	if !ok {
+		limits = DefaultLimits
+	}
+
+	if msg.Icon != noIcon {
+		limits.MaxWidth -= iconIndent
+	}
+
+	return limits
+}
+
+// Layout splits a message into boxes and lines and measures them.
+func Layout(msg *rom.Message, widths rom.FontWidths) []Box {
+	boxes := []Box{{}}
+	var line Line
+	var text strings.Builder
+
+	endLine := func() {
+		line.Text = text.String()
+		box := &boxes[len(boxes)-1]
+		box.Lines = append(box.Lines, line)
+		line = Line{}
+		text.Reset()
+	}
+
+	for _, token := range msg.Tokens {
+		switch token.Type {
+		case rom.MessageTokenText:
+			line.Width += measure(token.Text, widths)
+			text.WriteString(token.Text)
+		case rom.MessageTokenVariable:
+			placeholder, ok := variablePlaceholders[token.Name]
+			if !ok {
+				placeholder = defaultVariablePlaceholder
+			}
+			line.Width += measure(placeholder, widths)
+			text.WriteString(token.Markup())
+		case rom.MessageTokenButton:
+			width := float32(fallbackWidth)
+			if widths.Has(token.Code) {
+				width = widths.Width(token.Code) * Scale
+			}
+			line.Width += width
+			text.WriteString(token.Markup())
+		case rom.MessageTokenLineBreak:
+			endLine()
+		case rom.MessageTokenBoxBreak:
+			endLine()
+			boxes = append(boxes, Box{})
+		case rom.MessageTokenControl:
+			if token.Name == "shift" {
+				line.Width += float32(token.Value)
+			}
+		}
+	}
+	endLine()
+
+	return boxes
+}
+
+// measure returns the rendered width of a string.
+func measure(s string, widths rom.FontWidths) float32 {
+	var width float32
+	for _, r := range s {
+		b, ok := rom.MessageByte(r)
+		if ok && widths.Has(b) {
+			width += widths.Width(b) * Scale
+		} else {
+			width += fallbackWidth
+		}
+	}
+
+	return width
+}
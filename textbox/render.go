@@ -0,0 +1,212 @@
+package textbox
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+
+	"github.com/L-P/mme/rom"
+	"github.com/L-P/mme/texture"
+)
+
+// Rendered text box dimensions and text placement, in pixels.
+const (
+	BoxWidth   = 256
+	BoxHeight  = 64
+	boxSpacing = 4
+	textX      = 28
+	textY      = 6
+	lineHeight = 12
+)
+
+// boxColors is the tint of the text box background per TextBoxType, it is
+// drawn as a flat color when the type has no texture.
+var boxColors = map[byte]color.NRGBA{
+	0x00: {0x00, 0x00, 0x00, 0xAA}, // black
+	0x01: {0x6E, 0x46, 0x1E, 0xE6}, // wooden
+	0x02: {0x00, 0x0A, 0x50, 0xAA}, // blue
+	0x03: {0x00, 0x00, 0x00, 0xAA}, // ocarina
+	0x05: {0x00, 0x00, 0x00, 0x00}, // no box
+	0x0D: {0x8C, 0x82, 0x64, 0xE6}, // bomber's notebook
+}
+
+var defaultBoxColor = boxColors[0x00]
+
+// textColors maps color control codes to the color of the text they start.
+var textColors = map[byte]color.NRGBA{
+	0x00: {0xFF, 0xFF, 0xFF, 0xFF}, // default
+	0x01: {0xFF, 0x3C, 0x3C, 0xFF}, // red
+	0x02: {0x46, 0xFF, 0x50, 0xFF}, // green
+	0x03: {0x50, 0x6E, 0xFF, 0xFF}, // blue
+	0x04: {0xFF, 0xFF, 0x1E, 0xFF}, // yellow
+	0x05: {0x64, 0xB4, 0xFF, 0xFF}, // light blue
+	0x06: {0xFF, 0x96, 0xB4, 0xFF}, // pink
+	0x07: {0xAA, 0xAA, 0xAA, 0xFF}, // silver
+	0x08: {0xFF, 0xA0, 0x00, 0xFF}, // orange
+}
+
+// A Font holds what is needed to draw text.
+type Font struct {
+	Widths      rom.FontWidths
+	Glyph       func(b byte) []byte // I4 texture of a character
+	Backgrounds []byte              // message_static, nil to draw flat boxes
+}
+
+// renderer draws a message box by box.
+type renderer struct {
+	img   *image.NRGBA
+	font  Font
+	color color.NRGBA
+	box   int
+	x     float32
+	y     int
+}
+
+// Render draws a message as the game would, one text box below the other.
+func Render(w io.Writer, msg *rom.Message, font Font) error {
+	boxCount := 1
+	for _, token := range msg.Tokens {
+		if token.Type == rom.MessageTokenBoxBreak {
+			boxCount++
+		}
+	}
+
+	height := boxCount*BoxHeight + (boxCount-1)*boxSpacing
+	r := renderer{
+		img:  image.NewNRGBA(image.Rect(0, 0, BoxWidth, height)),
+		font: font,
+	}
+
+	for i := 0; i < boxCount; i++ {
+		r.drawBackground(i*(BoxHeight+boxSpacing), msg.TextBoxType)
+	}
+
+	left := float32(textX)
+	if msg.Icon != noIcon {
+		left += iconIndent
+	}
+
+	r.color = textColors[0x00]
+	r.x, r.y = left, textY
+	for _, token := range msg.Tokens {
+		switch token.Type {
+		case rom.MessageTokenText:
+			for _, c := range token.Text {
+				if b, ok := rom.MessageByte(c); ok {
+					r.drawCharacter(b)
+				}
+			}
+		case rom.MessageTokenButton:
+			r.drawCharacter(token.Code)
+		case rom.MessageTokenVariable:
+			placeholder, ok := variablePlaceholders[token.Name]
+			if !ok {
+				placeholder = defaultVariablePlaceholder
+			}
+			for _, c := range placeholder {
+				b, _ := rom.MessageByte(c)
+				r.drawCharacter(b)
+			}
+		case rom.MessageTokenColor:
+			r.color = textColors[token.Code]
+		case rom.MessageTokenLineBreak:
+			r.x, r.y = left, r.y+lineHeight
+		case rom.MessageTokenBoxBreak:
+			r.box++
+			r.x, r.y = left, textY
+		case rom.MessageTokenControl:
+			if token.Name == "shift" {
+				r.x += float32(token.Value)
+			}
+		}
+	}
+
+	return png.Encode(w, r.img)
+}
+
+// drawBackground draws the background of a text box at the given height,
+// using the box type texture tinted with its color when it has one.
+func (r *renderer) drawBackground(top int, boxType byte) {
+	tint, ok := boxColors[boxType]
+	if !ok {
+		tint = defaultBoxColor
+	}
+
+	bounds := image.Rect(0, top, BoxWidth, top+BoxHeight)
+	tex := background(r.font.Backgrounds, boxType)
+	if tex == nil {
+		draw.Draw(r.img, bounds, &image.Uniform{tint}, image.Point{}, draw.Src)
+		return
+	}
+
+	// The texture covers the left half, the right half is its mirror.
+	for y := 0; y < BoxHeight; y++ {
+		for x := 0; x < BoxWidth; x++ {
+			tx := x
+			if tx >= backgroundWidth {
+				tx = BoxWidth - 1 - x
+			}
+
+			c := tint
+			c.A = byte(int(tint.A) * int(tex.NRGBAAt(tx, y*backgroundHeight/BoxHeight).R) / 0xFF)
+			r.img.SetNRGBA(x, top+y, c)
+		}
+	}
+}
+
+// drawCharacter draws a glyph scaled down the way the game does, using its
+// intensity as the alpha of the current color, and advances the cursor.
+func (r *renderer) drawCharacter(b byte) {
+	width := float32(fallbackWidth)
+	if r.font.Widths.Has(b) {
+		width = r.font.Widths.Width(b) * Scale
+	}
+	defer func() { r.x += width }()
+
+	data := r.font.Glyph(b)
+	if data == nil {
+		return
+	}
+
+	glyph, err := texture.Decode(data, texture.I4, rom.FontGlyphSide, rom.FontGlyphSide)
+	if err != nil {
+		return
+	}
+
+	top := r.box*(BoxHeight+boxSpacing) + r.y
+	side := int(rom.FontGlyphSide * Scale)
+	for y := 0; y < side; y++ {
+		for x := 0; x < side; x++ {
+			src := glyph.NRGBAAt(int(float32(x)/Scale), int(float32(y)/Scale))
+			if src.R == 0 {
+				continue
+			}
+
+			dst := image.Point{int(r.x) + x, top + y}
+			if !dst.In(r.img.Bounds()) {
+				continue
+			}
+
+			c := r.color
+			c.A = byte(int(c.A) * int(src.R) / 0xFF)
+			r.img.Set(dst.X, dst.Y, blend(r.img.NRGBAAt(dst.X, dst.Y), c))
+		}
+	}
+}
+
+// blend draws src over dst.
+func blend(dst, src color.NRGBA) color.NRGBA {
+	a := int(src.A)
+	mix := func(d, s byte) byte {
+		return byte((int(s)*a + int(d)*(0xFF-a)) / 0xFF)
+	}
+
+	return color.NRGBA{
+		R: mix(dst.R, src.R),
+		G: mix(dst.G, src.G),
+		B: mix(dst.B, src.B),
+		A: byte(a + int(dst.A)*(0xFF-a)/0xFF),
+	}
+}
@@ -0,0 +1,162 @@
+// Package texture decodes N64 texture formats into images.
+// Sources:
+// - https://wiki.cloudmodding.com/oot/Textures
+package texture
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+)
+
+// Format is a N64 texture format and pixel size.
+type Format int
+
+// Known texture formats
+const (
+	RGBA16 Format = iota
+	RGBA32
+	I4
+	I8
+	IA4
+	IA8
+	IA16
+)
+
+var formatNames = map[Format]string{
+	RGBA16: "rgba16",
+	RGBA32: "rgba32",
+	I4:     "i4",
+	I8:     "i8",
+	IA4:    "ia4",
+	IA8:    "ia8",
+	IA16:   "ia16",
+}
+
+func (f Format) String() string {
+	if name, ok := formatNames[f]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("unknown format %d", f)
+}
+
+// ParseFormat returns the Format matching a name as returned by
+// Format.String.
+func ParseFormat(name string) (Format, error) {
+	for format, v := range formatNames {
+		if v == name {
+			return format, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unknown texture format %s", name)
+}
+
+// Size returns the number of bytes a texture of the given dimensions uses.
+func (f Format) Size(width, height int) int {
+	switch f {
+	case I4, IA4:
+		return (width*height + 1) / 2
+	case I8, IA8:
+		return width * height
+	case RGBA16, IA16:
+		return width * height * 2
+	case RGBA32:
+		return width * height * 4
+	}
+
+	return 0
+}
+
+// Decode decodes raw texture data into an image.
+func Decode(data []byte, format Format, width, height int) (*image.NRGBA, error) {
+	if width <= 0 || height <= 0 {
+		return nil, errors.New("invalid texture dimensions")
+	}
+
+	if size := format.Size(width, height); size <= 0 || len(data) < size {
+		return nil, fmt.Errorf(
+			"not enough data for a %dx%d %s texture, expected %d bytes got %d",
+			width, height, format, size, len(data),
+		)
+	}
+
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for i := 0; i < width*height; i++ {
+		img.Set(i%width, i/width, texel(data, format, i))
+	}
+
+	return img, nil
+}
+
+func texel(data []byte, format Format, i int) color.NRGBA {
+	switch format {
+	case I4:
+		v := nibble(data, i) * 0x11
+		return color.NRGBA{v, v, v, 0xFF}
+	case I8:
+		return color.NRGBA{data[i], data[i], data[i], 0xFF}
+	case IA4:
+		n := nibble(data, i)
+		v := (n >> 1) * 0x24
+		return color.NRGBA{v, v, v, (n & 1) * 0xFF}
+	case IA8:
+		v := (data[i] >> 4) * 0x11
+		return color.NRGBA{v, v, v, (data[i] & 0x0F) * 0x11}
+	case IA16:
+		return color.NRGBA{data[i*2], data[i*2], data[i*2], data[i*2+1]}
+	case RGBA16:
+		v := binary.BigEndian.Uint16(data[i*2:])
+		return color.NRGBA{
+			R: expand5(v >> 11),
+			G: expand5(v >> 6),
+			B: expand5(v >> 1),
+			A: byte(v&1) * 0xFF,
+		}
+	case RGBA32:
+		return color.NRGBA{data[i*4], data[i*4+1], data[i*4+2], data[i*4+3]}
+	}
+
+	return color.NRGBA{}
+}
+
+// nibble returns the i-th 4-bit value of data, high nibble first.
+func nibble(data []byte, i int) byte {
+	if i%2 == 0 {
+		return data[i/2] >> 4
+	}
+
+	return data[i/2] & 0x0F
+}
+
+// expand5 scales the 5 lower bits of v to a full byte.
+func expand5(v uint16) byte {
+	v &= 0x1F
+	return byte((v << 3) | (v >> 2))
+}
+
+// Strip lays out frames horizontally, from left to right, in a single image.
+// Frames are expected to all have the same size.
+func Strip(frames []image.Image) *image.NRGBA {
+	if len(frames) == 0 {
+		return image.NewNRGBA(image.Rect(0, 0, 0, 0))
+	}
+
+	size := frames[0].Bounds().Size()
+	img := image.NewNRGBA(image.Rect(0, 0, size.X*len(frames), size.Y))
+	for k, frame := range frames {
+		draw.Draw(
+			img,
+			image.Rect(k*size.X, 0, (k+1)*size.X, size.Y),
+			frame,
+			frame.Bounds().Min,
+			draw.Src,
+		)
+	}
+
+	return img
+}
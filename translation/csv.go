@@ -0,0 +1,64 @@
+package translation
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+)
+
+var csvColumns = []string{"ID", "Header", "Source", "Translation"}
+
+func writeCSV(w io.Writer, entries []Entry) error {
+	enc := csv.NewWriter(w)
+	if err := enc.Write(csvColumns); err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if err := enc.Write([]string{
+			formatID(entry.ID),
+			formatHeader(entry.Header),
+			entry.Source,
+			entry.Translation,
+		}); err != nil {
+			return err
+		}
+	}
+
+	enc.Flush()
+	return enc.Error()
+}
+
+func readCSV(r io.Reader) ([]Entry, error) {
+	dec := csv.NewReader(r)
+	dec.FieldsPerRecord = len(csvColumns)
+
+	records, err := dec.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(records) == 0 {
+		return nil, fmt.Errorf("empty CSV")
+	}
+
+	entries := make([]Entry, 0, len(records)-1)
+	for k, record := range records[1:] { // skip column names
+		id, err := parseID(record[0])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s", k+2, err)
+		}
+
+		entry := Entry{ID: id, Source: record[2], Translation: record[3]}
+		if record[1] != "" {
+			if entry.Header, err = parseHeader(record[1]); err != nil {
+				return nil, fmt.Errorf("line %d: %s", k+2, err)
+			}
+			entry.HasHeader = true
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
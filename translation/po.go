@@ -0,0 +1,150 @@
+package translation
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// gettext PO, message IDs are stored in msgctxt as the same source text can
+// appear in many messages, the header is stored in an extracted comment.
+// Sources:
+// - https://www.gnu.org/software/gettext/manual/html_node/PO-Files.html
+func writePO(w io.Writer, entries []Entry) error {
+	buf := bufio.NewWriter(w)
+	fmt.Fprint(buf, "msgid \"\"\nmsgstr \"\"\n\"Content-Type: text/plain; charset=UTF-8\\n\"\n")
+
+	for _, entry := range entries {
+		fmt.Fprintf(buf, "\n#. %s\n", formatHeader(entry.Header))
+		fmt.Fprintf(buf, "msgctxt %s\n", strconv.Quote(formatID(entry.ID)))
+		fmt.Fprintf(buf, "msgid %s\n", poQuote(entry.Source))
+		fmt.Fprintf(buf, "msgstr %s\n", poQuote(entry.Translation))
+	}
+
+	return buf.Flush()
+}
+
+// poQuote quotes a string, splitting multi-line strings after each newline
+// the way gettext tools do.
+func poQuote(s string) string {
+	lines := strings.SplitAfter(s, "\n")
+	if len(lines) <= 1 || (len(lines) == 2 && lines[1] == "") {
+		return strconv.Quote(s)
+	}
+
+	quoted := make([]string, 0, len(lines)+1)
+	quoted = append(quoted, `""`)
+	for _, line := range lines {
+		if line != "" {
+			quoted = append(quoted, strconv.Quote(line))
+		}
+	}
+
+	return strings.Join(quoted, "\n")
+}
+
+func readPO(r io.Reader) ([]Entry, error) {
+	entries := make([]Entry, 0, 1024)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var (
+		entry      Entry
+		hasContext bool
+		header     string
+		target     *string // keyword currently being read, for continuation lines
+		context    string
+		lineNumber int
+	)
+
+	flush := func() error {
+		defer func() {
+			entry, hasContext, header, context, target = Entry{}, false, "", "", nil
+		}()
+
+		if !hasContext {
+			// PO header or entry not generated by us, the former is expected.
+			if entry.Source == "" {
+				return nil
+			}
+			return fmt.Errorf("line %d: entry without msgctxt (missing ID)", lineNumber)
+		}
+
+		id, err := parseID(context)
+		if err != nil {
+			return fmt.Errorf("line %d: %s", lineNumber, err)
+		}
+		entry.ID = id
+
+		if header != "" {
+			if entry.Header, err = parseHeader(header); err != nil {
+				return fmt.Errorf("line %d: %s", lineNumber, err)
+			}
+			entry.HasHeader = true
+		}
+
+		entries = append(entries, entry)
+		return nil
+	}
+
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+
+		var keyword, rest string
+		switch {
+		case line == "":
+			if err := flush(); err != nil {
+				return nil, err
+			}
+			continue
+		case strings.HasPrefix(line, "#."):
+			header = strings.TrimSpace(line[2:])
+			continue
+		case strings.HasPrefix(line, "#"):
+			continue
+		case strings.HasPrefix(line, `"`):
+			rest = line
+		default:
+			parts := strings.SplitN(line, " ", 2)
+			if len(parts) != 2 {
+				return nil, fmt.Errorf("line %d: invalid syntax", lineNumber)
+			}
+			keyword, rest = parts[0], parts[1]
+		}
+
+		value, err := strconv.Unquote(rest)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid string %s", lineNumber, rest)
+		}
+
+		switch keyword {
+		case "":
+			if target == nil {
+				return nil, fmt.Errorf("line %d: unexpected string", lineNumber)
+			}
+		case "msgctxt":
+			target, hasContext = &context, true
+		case "msgid":
+			target = &entry.Source
+		case "msgstr":
+			target = &entry.Translation
+		default:
+			return nil, fmt.Errorf("line %d: unsupported keyword %s", lineNumber, keyword)
+		}
+
+		*target += value
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := flush(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
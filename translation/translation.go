@@ -0,0 +1,263 @@
+// Package translation exports messages to formats translators work with and
+// imports them back.
+package translation
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/L-P/mme/rom"
+)
+
+// Format is a translation file format.
+type Format string
+
+// Supported formats
+const (
+	PO    Format = "po"
+	CSV   Format = "csv"
+	XLIFF Format = "xliff"
+)
+
+// ParseFormat returns the Format matching a name or file extension.
+func ParseFormat(name string) (Format, error) {
+	name = strings.ToLower(strings.TrimPrefix(name, "."))
+	switch Format(name) {
+	case PO, CSV, XLIFF:
+		return Format(name), nil
+	case "xlf":
+		return XLIFF, nil
+	}
+
+	return "", fmt.Errorf("unknown translation format %s", name)
+}
+
+// An Entry is a single message to translate.
+type Entry struct {
+	ID        uint16
+	Header    rom.MessageHeader
+	HasHeader bool // false if the file did not provide the header
+
+	Source      string // original markup
+	Translation string // translated markup, empty if untranslated
+}
+
+// A Problem is an issue found while validating an imported Entry.
+type Problem struct {
+	ID      uint16
+	Message string
+}
+
+func (p Problem) Error() string {
+	return fmt.Sprintf("message 0x%04X: %s", p.ID, p.Message)
+}
+
+// Export writes all messages in the given format.
+func Export(w io.Writer, messages []rom.Message, format Format) error {
+	entries := make([]Entry, len(messages), len(messages))
+	for k, msg := range messages {
+		entries[k] = Entry{
+			ID:        msg.ID,
+			Header:    msg.MessageHeader,
+			HasHeader: true,
+			Source:    msg.Markup,
+		}
+	}
+
+	switch format {
+	case PO:
+		return writePO(w, entries)
+	case CSV:
+		return writeCSV(w, entries)
+	case XLIFF:
+		return writeXLIFF(w, entries)
+	}
+
+	return fmt.Errorf("unknown translation format %s", format)
+}
+
+// Import reads entries in the given format.
+func Import(r io.Reader, format Format) ([]Entry, error) {
+	switch format {
+	case PO:
+		return readPO(r)
+	case CSV:
+		return readCSV(r)
+	case XLIFF:
+		return readXLIFF(r)
+	}
+
+	return nil, fmt.Errorf("unknown translation format %s", format)
+}
+
+// Validate checks translated entries against the ROM messages: IDs must
+// exist, markup must only use known control codes, choices must match the
+// original message, and the ROM must have enough space for all of them.
+func Validate(entries []Entry, view *rom.View) []Problem {
+	problems := make([]Problem, 0)
+	seen := make(map[uint16]bool, len(entries))
+	sizes := make([]rom.MessageSize, 0, len(entries))
+
+	for _, entry := range entries {
+		if seen[entry.ID] {
+			problems = append(problems, Problem{entry.ID, "duplicate ID"})
+		}
+		seen[entry.ID] = true
+
+		msg, err := view.GetMessageByID(entry.ID)
+		if err != nil {
+			problems = append(problems, Problem{entry.ID, "ID does not exist in ROM"})
+			continue
+		}
+
+		if entry.Translation == "" {
+			continue
+		}
+
+		tokens, err := rom.ParseMessageMarkup(entry.Translation)
+		if err != nil {
+			problems = append(problems, Problem{entry.ID, err.Error()})
+			continue
+		}
+
+		if _, err := rom.EncodeMessageTokens(tokens); err != nil {
+			problems = append(problems, Problem{entry.ID, err.Error()})
+			continue
+		}
+
+		if encoded, err := rom.EncodeMessage(entryHeader(entry, msg), entry.Translation); err == nil {
+			sizes = append(sizes, rom.MessageSize{ID: entry.ID, Size: len(encoded)})
+		}
+
+		expected, got := countChoices(msg.Tokens), countChoices(tokens)
+		for name, count := range expected {
+			if got[name] != count {
+				problems = append(problems, Problem{entry.ID, fmt.Sprintf(
+					"unmatched choices, expected %d {%s} got %d", count, name, got[name],
+				)})
+			}
+		}
+		for name, count := range got {
+			if _, ok := expected[name]; !ok {
+				problems = append(problems, Problem{entry.ID, fmt.Sprintf(
+					"unmatched choices, expected 0 {%s} got %d", name, count,
+				)})
+			}
+		}
+	}
+
+	if len(problems) == 0 {
+		if id, err := view.CheckMessageSpace(sizes); err != nil {
+			problems = append(problems, Problem{id, err.Error()})
+		}
+	}
+
+	return problems
+}
+
+// entryHeader returns the header an entry is applied with.
+func entryHeader(entry Entry, msg *rom.Message) rom.MessageHeader {
+	if entry.HasHeader {
+		return entry.Header
+	}
+
+	return msg.MessageHeader
+}
+
+func countChoices(tokens []rom.MessageToken) map[string]int {
+	counts := make(map[string]int)
+	for _, token := range tokens {
+		if token.Type == rom.MessageTokenChoice {
+			counts[token.Name]++
+		}
+	}
+
+	return counts
+}
+
+// Apply writes translated entries to the ROM, untranslated entries are
+// skipped. Entries should be validated first, nothing is written if they do
+// not all fit in the ROM.
+func Apply(entries []Entry, view *rom.View) (int, error) {
+	if problems := Validate(entries, view); len(problems) > 0 {
+		return 0, problems[0]
+	}
+
+	applied := 0
+	for _, entry := range entries {
+		if entry.Translation == "" {
+			continue
+		}
+
+		msg, err := view.GetMessageByID(entry.ID)
+		if err != nil {
+			return applied, err
+		}
+
+		if err := view.SetMessage(entry.ID, entryHeader(entry, msg), entry.Translation); err != nil {
+			return applied, err
+		}
+		applied++
+	}
+
+	return applied, nil
+}
+
+// formatID returns a message ID as written in translation files.
+func formatID(id uint16) string {
+	return fmt.Sprintf("0x%04X", id)
+}
+
+func parseID(s string) (uint16, error) {
+	id, err := strconv.ParseUint(strings.TrimSpace(s), 0, 16)
+	if err != nil {
+		return 0, fmt.Errorf("invalid or missing message ID %q", s)
+	}
+
+	return uint16(id), nil
+}
+
+// formatHeader returns the header fields as a space-separated list of
+// key=value pairs, used in comments and notes.
+func formatHeader(h rom.MessageHeader) string {
+	return fmt.Sprintf(
+		"TextBoxType=0x%02X TextBoxPosition=0x%02X Icon=0x%02X NextMessageNumber=0x%04X RupeeCost=0x%04X",
+		h.TextBoxType,
+		h.TextBoxPosition,
+		h.Icon,
+		h.NextMessageNumber,
+		h.RupeeCost,
+	)
+}
+
+// parseHeader parses what formatHeader returns.
+func parseHeader(s string) (rom.MessageHeader, error) {
+	var h rom.MessageHeader
+	fields := map[string]int{}
+	for _, pair := range strings.Fields(s) {
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 {
+			return h, fmt.Errorf("invalid header field %q", pair)
+		}
+
+		v, err := strconv.ParseUint(parts[1], 0, 16)
+		if err != nil {
+			return h, fmt.Errorf("invalid header field %q", pair)
+		}
+		fields[parts[0]] = int(v)
+	}
+
+	if len(fields) != 5 {
+		return h, fmt.Errorf("incomplete header %q", s)
+	}
+
+	h.TextBoxType = byte(fields["TextBoxType"])
+	h.TextBoxPosition = byte(fields["TextBoxPosition"])
+	h.Icon = byte(fields["Icon"])
+	h.NextMessageNumber = uint16(fields["NextMessageNumber"])
+	h.RupeeCost = uint16(fields["RupeeCost"])
+
+	return h, nil
+}
@@ -0,0 +1,118 @@
+package translation
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/L-P/mme/rom"
+)
+
+var testEntries = []Entry{
+	{
+		ID:          0x0000,
+		Header:      rom.MessageHeader{TextBoxType: 0x00, Icon: 0xFE, NextMessageNumber: 0xFFFF, RupeeCost: 0xFFFF},
+		HasHeader:   true,
+		Source:      "Hello!",
+		Translation: "Bonjour !",
+	},
+	{
+		ID:          0x0102,
+		Header:      rom.MessageHeader{TextBoxType: 0x02, TextBoxPosition: 0x01, Icon: 0x10, NextMessageNumber: 0x0103, RupeeCost: 20},
+		HasHeader:   true,
+		Source:      "{color:red}Red Potion{color:default}\n20 Rupees\n{choice2}",
+		Translation: "{color:red}Potion rouge{color:default}\n20 rubis\n{choice2}",
+	},
+	{
+		ID:        0x1FFF,
+		Header:    rom.MessageHeader{NextMessageNumber: 0xFFFF, RupeeCost: 0xFFFF},
+		HasHeader: true,
+		Source:    "Quotes \", commas, <tags> & {{braces}\n\nand blank lines\n",
+	},
+}
+
+func TestRoundTrip(t *testing.T) {
+	cases := []struct {
+		format Format
+		write  func(io.Writer, []Entry) error
+	}{
+		{PO, writePO},
+		{CSV, writeCSV},
+		{XLIFF, writeXLIFF},
+	}
+
+	for _, c := range cases {
+		t.Run(string(c.format), func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := c.write(&buf, testEntries); err != nil {
+				t.Fatal(err)
+			}
+
+			entries, err := Import(&buf, c.format)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(entries, testEntries) {
+				t.Fatalf("expected %+v, got %+v", testEntries, entries)
+			}
+		})
+	}
+}
+
+func TestExport(t *testing.T) {
+	messages := make([]rom.Message, len(testEntries), len(testEntries))
+	for k, entry := range testEntries {
+		messages[k].ID = entry.ID
+		messages[k].MessageHeader = entry.Header
+		messages[k].Markup = entry.Source
+	}
+
+	for _, format := range []Format{PO, CSV, XLIFF} {
+		t.Run(string(format), func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Export(&buf, messages, format); err != nil {
+				t.Fatal(err)
+			}
+
+			entries, err := Import(&buf, format)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(entries) != len(testEntries) {
+				t.Fatalf("expected %d entries, got %d", len(testEntries), len(entries))
+			}
+			for k, entry := range entries {
+				expected := testEntries[k]
+				expected.Translation = ""
+				if entry != expected {
+					t.Errorf("expected %+v, got %+v", expected, entry)
+				}
+			}
+		})
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	cases := []struct {
+		name     string
+		expected Format
+		err      bool
+	}{
+		{"po", PO, false},
+		{".PO", PO, false},
+		{"csv", CSV, false},
+		{"xliff", XLIFF, false},
+		{".xlf", XLIFF, false},
+		{"json", "", true},
+	}
+
+	for _, c := range cases {
+		format, err := ParseFormat(c.name)
+		if (err != nil) != c.err || format != c.expected {
+			t.Errorf("%s: expected %q (error %t), got %q (%v)", c.name, c.expected, c.err, format, err)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package translation
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+)
+
+// XLIFF 1.2 document, only the parts we use.
+// Sources:
+// - http://docs.oasis-open.org/xliff/v1.2/os/xliff-core.html
+type xliffDocument struct {
+	XMLName xml.Name  `xml:"urn:oasis:names:tc:xliff:document:1.2 xliff"`
+	Version string    `xml:"version,attr"`
+	File    xliffFile `xml:"file"`
+}
+
+type xliffFile struct {
+	Original       string           `xml:"original,attr"`
+	SourceLanguage string           `xml:"source-language,attr"`
+	Datatype       string           `xml:"datatype,attr"`
+	Units          []xliffTransUnit `xml:"body>trans-unit"`
+}
+
+type xliffTransUnit struct {
+	ID     string `xml:"id,attr"`
+	Source string `xml:"source"`
+	Target string `xml:"target,omitempty"`
+	Note   string `xml:"note,omitempty"`
+}
+
+func writeXLIFF(w io.Writer, entries []Entry) error {
+	doc := xliffDocument{
+		Version: "1.2",
+		File: xliffFile{
+			Original:       "nes_message_data_static",
+			SourceLanguage: "en",
+			Datatype:       "plaintext",
+			Units:          make([]xliffTransUnit, len(entries), len(entries)),
+		},
+	}
+
+	for k, entry := range entries {
+		doc.File.Units[k] = xliffTransUnit{
+			ID:     formatID(entry.ID),
+			Source: entry.Source,
+			Target: entry.Translation,
+			Note:   formatHeader(entry.Header),
+		}
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	return enc.Encode(doc)
+}
+
+func readXLIFF(r io.Reader) ([]Entry, error) {
+	var doc xliffDocument
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	entries := make([]Entry, 0, len(doc.File.Units))
+	for k, unit := range doc.File.Units {
+		id, err := parseID(unit.ID)
+		if err != nil {
+			return nil, fmt.Errorf("trans-unit #%d: %s", k, err)
+		}
+
+		entry := Entry{ID: id, Source: unit.Source, Translation: unit.Target}
+		if unit.Note != "" {
+			if entry.Header, err = parseHeader(unit.Note); err != nil {
+				return nil, fmt.Errorf("trans-unit %s: %s", unit.ID, err)
+			}
+			entry.HasHeader = true
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}